@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
 
 	cfg "github.com/conductorone/baton-salesforce/pkg/config"
 	"github.com/conductorone/baton-salesforce/pkg/connector"
@@ -13,6 +15,16 @@ var version = "dev"
 
 func main() {
 	ctx := context.Background()
+	// baton-sdk builds the root command itself, so the one subcommand of our
+	// own is dispatched before handing over to it.
+	if len(os.Args) > 1 && os.Args[1] == oauthLoginCommand {
+		if err := runOAuthLogin(ctx, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	config.RunConnector(ctx,
 		"baton-salesforce",
 		version,
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oauthLoginCommand = "oauth-login"
+	// defaultCallbackURL is the callback the Salesforce CLI uses, which many
+	// apps already allow.
+	defaultCallbackURL = "http://localhost:1717/OauthRedirect"
+	oauthLoginTimeout  = 5 * time.Minute
+)
+
+type authorizationResult struct {
+	code string
+	err  error
+}
+
+// runOAuthLogin runs the OAuth 2.0 Web Server flow once on this machine and
+// prints the refresh token that the refresh-token-group auth method needs.
+// The app's callback URL must match --callback-url, which this command
+// listens on to receive the authorization code. The app must not rotate
+// refresh tokens, since the connector only ever has the one printed here.
+func runOAuthLogin(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet(oauthLoginCommand, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: baton-salesforce %s [flags]\n\n", oauthLoginCommand)
+		fmt.Fprintln(flags.Output(), "Authorize the connector in a browser and print a refresh token for the OAuth Refresh Token auth method.")
+		fmt.Fprintln(flags.Output(), "Refresh token rotation must be off on the app, since the connector can't store a rotated refresh token.")
+		fmt.Fprintln(flags.Output())
+		flags.PrintDefaults()
+	}
+	instanceURL := flags.String("instance-url", os.Getenv("BATON_INSTANCE_URL"), "Your Salesforce domain, ex: acme.my.salesforce.com ($BATON_INSTANCE_URL)")
+	clientID := flags.String("salesforce-client-id", os.Getenv("BATON_SALESFORCE_CLIENT_ID"), "Consumer Key of the app ($BATON_SALESFORCE_CLIENT_ID)")
+	clientSecret := flags.String("salesforce-client-secret", os.Getenv("BATON_SALESFORCE_CLIENT_SECRET"), "Consumer Secret of the app ($BATON_SALESFORCE_CLIENT_SECRET)")
+	callbackURL := flags.String("callback-url", defaultCallbackURL, "Callback URL configured on the app; must point at this machine")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *instanceURL == "" || *clientID == "" || *clientSecret == "" {
+		flags.Usage()
+		return errors.New("baton-salesforce: --instance-url, --salesforce-client-id and --salesforce-client-secret are required")
+	}
+	if !strings.Contains(*instanceURL, "://") {
+		*instanceURL = "https://" + *instanceURL
+	}
+
+	callback, err := url.Parse(*callbackURL)
+	if err != nil {
+		return fmt.Errorf("baton-salesforce: invalid callback URL: %w", err)
+	}
+	if callback.Host == "" {
+		return fmt.Errorf("baton-salesforce: callback URL %q has no host to listen on, ex: %s", *callbackURL, defaultCallbackURL)
+	}
+	cfg, err := client.NewWebServerConfig(*clientID, *clientSecret, *instanceURL, callback.String())
+	if err != nil {
+		return err
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	listener, err := net.Listen("tcp", callback.Host)
+	if err != nil {
+		return fmt.Errorf("baton-salesforce: failed to listen for the callback on %s: %w", callback.Host, err)
+	}
+	results := make(chan authorizationResult, 1)
+	server := &http.Server{
+		Handler:           callbackHandler(callback.Path, state, results),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	authURL := cfg.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+	fmt.Fprintf(os.Stderr, "Open this URL in a browser and log in as the user the connector should run as:\n\n%s\n\n", authURL)
+
+	ctx, cancel := context.WithTimeout(ctx, oauthLoginTimeout)
+	defer cancel()
+	var result authorizationResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return fmt.Errorf("baton-salesforce: no authorization received: %w", ctx.Err())
+	}
+	if result.err != nil {
+		return result.err
+	}
+
+	token, err := cfg.Exchange(ctx, result.code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return fmt.Errorf("baton-salesforce: authorization code exchange failed: %w", err)
+	}
+	if token.RefreshToken == "" {
+		return errors.New("baton-salesforce: no refresh token issued; add the refresh_token scope to the app's OAuth scopes")
+	}
+
+	fmt.Fprintln(os.Stderr, "Authorized. Run the connector with the refresh token auth method and:")
+	fmt.Printf("BATON_SALESFORCE_REFRESH_TOKEN=%s\n", token.RefreshToken)
+	return nil
+}
+
+// callbackHandler receives the redirect back from Salesforce and hands the
+// authorization code, or the error Salesforce reported, to results. A
+// callback URL without a path is served at the root.
+func callbackHandler(path string, state string, results chan<- authorizationResult) http.Handler {
+	if path == "" {
+		path = "/"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var result authorizationResult
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("baton-salesforce: authorization failed: %s: %s", query.Get("error"), query.Get("error_description"))
+		default:
+			result.code = query.Get("code")
+		}
+		if result.err != nil {
+			http.Error(w, "Authorization failed, see the terminal for details. You can close this window.", http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Authorized. You can close this window.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	return mux
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("baton-salesforce: failed to generate OAuth state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -12,12 +12,20 @@ type Salesforce struct {
 	SyncConnectedApps bool `mapstructure:"sync-connected-apps"`
 	SyncDeactivatedUsers bool `mapstructure:"sync-deactivated-users"`
 	SyncNonStandardUsers bool `mapstructure:"sync-non-standard-users"`
+	SyncQueues bool `mapstructure:"sync-queues"`
+	RoleHierarchyAccess bool `mapstructure:"role-hierarchy-access"`
+	SyncObjectPermissions bool `mapstructure:"sync-object-permissions"`
+	HighRiskSystemPermissions []string `mapstructure:"high-risk-system-permissions"`
+	FieldPermissions []string `mapstructure:"field-permissions"`
 	LicenseToLeastPrivilegedProfileMapping map[string]any `mapstructure:"license-to-least-privileged-profile-mapping"`
+	BulkQueryThreshold int `mapstructure:"bulk-query-threshold"`
+	ApiBudgetPercent int `mapstructure:"api-budget-percent"`
 	Oauth2Token string `mapstructure:"oauth2-token"`
 	SalesforceClientId string `mapstructure:"salesforce-client-id"`
 	SalesforceClientSecret string `mapstructure:"salesforce-client-secret"`
 	SalesforcePrivateKey []byte `mapstructure:"salesforce-private-key"`
 	SalesforceJwtSubject string `mapstructure:"salesforce-jwt-subject"`
+	SalesforceRefreshToken string `mapstructure:"salesforce-refresh-token"`
 	SalesforceLoginUrl string `mapstructure:"salesforce-login-url"`
 }
 
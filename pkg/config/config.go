@@ -11,6 +11,7 @@ const (
 	SalesforceOAuthGroup             = "oauth-group"
 	SalesforceJWTBearerGroup         = "jwt-bearer-group" //nolint:gosec // false positive: "bearer" is an auth method name, not a credential
 	SalesforceClientCredentialsGroup = "client-credentials-group"
+	SalesforceRefreshTokenGroup      = "refresh-token-group"
 )
 
 var (
@@ -66,11 +67,51 @@ var (
 		field.WithDescription("Optionally sync non-standard user types (Customer Community, etc)"),
 		field.WithDefaultValue(false),
 	)
+	SyncQueues = field.BoolField(
+		"sync-queues",
+		field.WithDisplayName("Sync Queues"),
+		field.WithDescription("Sync queues as their own resource type, along with the object types they handle, instead of as groups"),
+		field.WithDefaultValue(false),
+	)
+	RoleHierarchyAccess = field.BoolField(
+		"role-hierarchy-access",
+		field.WithDisplayName("Role Hierarchy Access"),
+		field.WithDescription("Add a subordinate access entitlement to each role, held by the users of every role above it in the role hierarchy"),
+		field.WithDefaultValue(false),
+	)
+	SyncObjectPermissions = field.BoolField(
+		"sync-object-permissions",
+		field.WithDisplayName("Sync Object Permissions"),
+		field.WithDescription("Sync the object permissions (Read, Create, Edit, Delete, View All, Modify All) of permission sets and profiles as read-only entitlements"),
+		field.WithDefaultValue(false),
+	)
+	HighRiskSystemPermissions = field.StringSliceField(
+		"high-risk-system-permissions",
+		field.WithDisplayName("High-Risk System Permissions"),
+		field.WithDescription("System permissions to flag on permission sets and profiles, by API name, e.g. ModifyAllData, ViewAllData, ManageUsers, AuthorApex, CustomizeApplication"),
+	)
+	FieldPermissions = field.StringSliceField(
+		"field-permissions",
+		field.WithDisplayName("Field Permissions"),
+		field.WithDescription("Fields to sync field-level security for, as SobjectType.Field, e.g. Contact.SSN__c"),
+	)
 	LicenseToLeastPrivilegedProfileMapping = field.StringMapField(
 		"license-to-least-privileged-profile-mapping",
 		field.WithDisplayName("License to Least Privileged Profile Mapping"),
 		field.WithDescription("Mapping of Salesforce license types to least privileged profiles"),
 	)
+	BulkQueryThresholdField = field.IntField(
+		"bulk-query-threshold",
+		field.WithDisplayName("Bulk Query Threshold"),
+		field.WithDescription("Use the Salesforce Bulk API 2.0 for user and assignment queries that match more than this many rows. 0 disables bulk queries"),
+		field.WithDefaultValue(0),
+	)
+	APIBudgetPercentField = field.IntField(
+		"api-budget-percent",
+		field.WithDisplayName("API Budget Percent"),
+		field.WithDescription("Never use more than this percentage of the org's daily API request limit, which is shared with your other integrations. 0 disables the budget"),
+		field.WithDefaultValue(0),
+	)
 	ClientIDField = field.StringField(
 		"salesforce-client-id",
 		field.WithDisplayName("Client ID"),
@@ -98,6 +139,13 @@ var (
 		field.WithDescription("Salesforce username of the integration user the connector will authenticate as"),
 		field.WithRequired(true),
 	)
+	RefreshTokenField = field.StringField(
+		"salesforce-refresh-token",
+		field.WithDisplayName("Refresh Token"),
+		field.WithDescription("OAuth refresh token from the Web Server flow. Run `baton-salesforce oauth-login` to get one. The app must not rotate refresh tokens"),
+		field.WithIsSecret(true),
+		field.WithRequired(true),
+	)
 	LoginURLField = field.StringField(
 		"salesforce-login-url",
 		field.WithDisplayName("Login URL"),
@@ -114,12 +162,20 @@ var (
 		SyncConnectedApps,
 		SyncDeactivatedUsers,
 		SyncNonStandardUsers,
+		SyncQueues,
+		RoleHierarchyAccess,
+		SyncObjectPermissions,
+		HighRiskSystemPermissions,
+		FieldPermissions,
 		LicenseToLeastPrivilegedProfileMapping,
+		BulkQueryThresholdField,
+		APIBudgetPercentField,
 		Oauth2TokenField,
 		ClientIDField,
 		ClientSecretField,
 		PrivateKeyField,
 		JWTSubjectField,
+		RefreshTokenField,
 		LoginURLField,
 	}
 
@@ -142,7 +198,15 @@ var (
 					SyncConnectedApps,
 					SyncDeactivatedUsers,
 					SyncNonStandardUsers,
-					LicenseToLeastPrivilegedProfileMapping},
+					SyncQueues,
+					RoleHierarchyAccess,
+					SyncObjectPermissions,
+					HighRiskSystemPermissions,
+					FieldPermissions,
+					LicenseToLeastPrivilegedProfileMapping,
+					BulkQueryThresholdField,
+					APIBudgetPercentField,
+				},
 				Default: false,
 			},
 			{
@@ -155,7 +219,14 @@ var (
 					SyncConnectedApps,
 					SyncDeactivatedUsers,
 					SyncNonStandardUsers,
+					SyncQueues,
+					RoleHierarchyAccess,
+					SyncObjectPermissions,
+					HighRiskSystemPermissions,
+					FieldPermissions,
 					LicenseToLeastPrivilegedProfileMapping,
+					BulkQueryThresholdField,
+					APIBudgetPercentField,
 					Oauth2TokenField,
 				},
 				Default: true,
@@ -174,7 +245,14 @@ var (
 					SyncConnectedApps,
 					SyncDeactivatedUsers,
 					SyncNonStandardUsers,
+					SyncQueues,
+					RoleHierarchyAccess,
+					SyncObjectPermissions,
+					HighRiskSystemPermissions,
+					FieldPermissions,
 					LicenseToLeastPrivilegedProfileMapping,
+					BulkQueryThresholdField,
+					APIBudgetPercentField,
 				},
 				Default: false,
 			},
@@ -190,7 +268,38 @@ var (
 					SyncConnectedApps,
 					SyncDeactivatedUsers,
 					SyncNonStandardUsers,
+					SyncQueues,
+					RoleHierarchyAccess,
+					SyncObjectPermissions,
+					HighRiskSystemPermissions,
+					FieldPermissions,
+					LicenseToLeastPrivilegedProfileMapping,
+					BulkQueryThresholdField,
+					APIBudgetPercentField,
+				},
+				Default: false,
+			},
+			{
+				Name:        SalesforceRefreshTokenGroup,
+				DisplayName: "OAuth Refresh Token",
+				HelpText:    "Authenticate as a user with a refresh token from the OAuth Web Server flow, for self-hosted deployments.",
+				Fields: []field.SchemaField{
+					InstanceUrlField,
+					ClientIDField,
+					ClientSecretField,
+					RefreshTokenField,
+					UseUsernameForEmailField,
+					SyncConnectedApps,
+					SyncDeactivatedUsers,
+					SyncNonStandardUsers,
+					SyncQueues,
+					RoleHierarchyAccess,
+					SyncObjectPermissions,
+					HighRiskSystemPermissions,
+					FieldPermissions,
 					LicenseToLeastPrivilegedProfileMapping,
+					BulkQueryThresholdField,
+					APIBudgetPercentField,
 				},
 				Default: false,
 			},
@@ -2,7 +2,10 @@ package connector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"github.com/conductorone/baton-salesforce/pkg/connector/client"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -11,6 +14,10 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
+// sObjectNamePattern matches the API name of a standard or custom object. The
+// deprovision_user objects end up in SOQL, so anything else is rejected.
+var sObjectNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
 func (s *Salesforce) updateUserStatus(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
 	l := ctxzap.Extract(ctx)
 
@@ -51,3 +58,197 @@ func (s *Salesforce) updateUserStatus(ctx context.Context, args *structpb.Struct
 
 	return &response, outputAnnotations, nil
 }
+
+func (s *Salesforce) freezeUser(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	return s.setUserFrozen(ctx, args, true)
+}
+
+func (s *Salesforce) unfreezeUser(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	return s.setUserFrozen(ctx, args, false)
+}
+
+// setUserFrozen freezes or unfreezes a user. Freezing is the emergency stop
+// for users that Salesforce won't let us deactivate yet.
+func (s *Salesforce) setUserFrozen(
+	ctx context.Context,
+	args *structpb.Struct,
+	frozen bool,
+) (*structpb.Struct, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	guidField, ok := args.Fields["resource_id"].GetKind().(*structpb.Value_StringValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("missing resource ID")
+	}
+
+	userId := guidField.StringValue
+
+	userLogin, ratelimitData, err := s.client.SetUserFrozen(ctx, userId, frozen)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		l.Error("Failed to update user frozen state",
+			zap.String("resource_id", userId),
+			zap.Bool("is_frozen", frozen),
+			zap.Error(err))
+
+		if errors.Is(err, client.ErrObjectNotFound) {
+			return nil, outputAnnotations, fmt.Errorf("user %s has no login to freeze or unfreeze: %w", userId, err)
+		}
+		return nil, outputAnnotations, err
+	}
+
+	response := structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"success": {
+				Kind: &structpb.Value_BoolValue{BoolValue: true},
+			},
+			"is_frozen": {
+				Kind: &structpb.Value_BoolValue{BoolValue: userLogin.IsFrozen},
+			},
+		},
+	}
+
+	return &response, outputAnnotations, nil
+}
+
+func (s *Salesforce) unlockUser(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	guidField, ok := args.Fields["resource_id"].GetKind().(*structpb.Value_StringValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("missing resource ID")
+	}
+
+	userId := guidField.StringValue
+
+	userLogin, ratelimitData, err := s.client.UnlockUser(ctx, userId)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		l.Error("Failed to unlock user",
+			zap.String("resource_id", userId),
+			zap.Error(err))
+
+		if errors.Is(err, client.ErrObjectNotFound) {
+			return nil, outputAnnotations, fmt.Errorf("user %s has no login to unlock: %w", userId, err)
+		}
+		return nil, outputAnnotations, err
+	}
+
+	response := structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"success": {
+				Kind: &structpb.Value_BoolValue{BoolValue: true},
+			},
+			"is_password_locked": {
+				Kind: &structpb.Value_BoolValue{BoolValue: userLogin.IsPasswordLocked},
+			},
+		},
+	}
+
+	return &response, outputAnnotations, nil
+}
+
+// deprovisionUser hands a user's records to a successor and strips their
+// group, queue and territory memberships before deactivating them. Salesforce
+// refuses to deactivate users that still own queues or records in some
+// setups, and deactivating first would leave their open records orphaned.
+func (s *Salesforce) deprovisionUser(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	guidField, ok := args.Fields["resource_id"].GetKind().(*structpb.Value_StringValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("missing resource ID")
+	}
+
+	successorField, ok := args.Fields["successor_id"].GetKind().(*structpb.Value_StringValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("missing successor ID")
+	}
+
+	userId := guidField.StringValue
+	successorId := successorField.StringValue
+	if userId == successorId {
+		return nil, nil, fmt.Errorf("successor must be a different user than %s", userId)
+	}
+
+	objects := client.DefaultOwnershipTransferObjects
+	if objectsField := args.Fields["objects"].GetListValue(); objectsField != nil && len(objectsField.Values) > 0 {
+		objects = make([]string, 0, len(objectsField.Values))
+		for _, value := range objectsField.Values {
+			object, ok := value.GetKind().(*structpb.Value_StringValue)
+			if !ok || object.StringValue == "" {
+				return nil, nil, fmt.Errorf("objects must be a list of Salesforce object names")
+			}
+			if !sObjectNamePattern.MatchString(object.StringValue) {
+				return nil, nil, fmt.Errorf("invalid Salesforce object name %q", object.StringValue)
+			}
+			objects = append(objects, object.StringValue)
+		}
+	}
+
+	transferred := make(map[string]*structpb.Value, len(objects))
+	totalTransferred := 0
+	removedGroups := 0
+	removedTerritories := 0
+	// The steps aren't undone when a later one fails, so the response always
+	// reports what was done, alongside the error if there is one.
+	response := func(success bool) *structpb.Struct {
+		return &structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				"success":                       structpb.NewBoolValue(success),
+				"transferred":                   structpb.NewStructValue(&structpb.Struct{Fields: transferred}),
+				"transferred_records":           structpb.NewNumberValue(float64(totalTransferred)),
+				"removed_group_memberships":     structpb.NewNumberValue(float64(removedGroups)),
+				"removed_territory_memberships": structpb.NewNumberValue(float64(removedTerritories)),
+			},
+		}
+	}
+
+	for _, object := range objects {
+		count, ratelimitData, err := s.client.TransferOwnership(ctx, object, userId, successorId)
+		transferred[object] = structpb.NewStringValue(strconv.Itoa(count))
+		totalTransferred += count
+		if err != nil {
+			l.Error("Failed to transfer record ownership",
+				zap.String("resource_id", userId),
+				zap.String("successor_id", successorId),
+				zap.String("sobject_type", object),
+				zap.Int("transferred", count),
+				zap.Error(err))
+
+			return response(false), client.WithRateLimitAnnotations(ratelimitData), err
+		}
+	}
+
+	removedGroups, ratelimitData, err := s.client.RemoveGroupMemberships(ctx, userId)
+	if err != nil {
+		l.Error("Failed to remove group memberships",
+			zap.String("resource_id", userId),
+			zap.Int("removed", removedGroups),
+			zap.Error(err))
+
+		return response(false), client.WithRateLimitAnnotations(ratelimitData), err
+	}
+
+	removedTerritories, ratelimitData, err = s.client.RemoveTerritoryMemberships(ctx, userId)
+	if err != nil {
+		l.Error("Failed to remove territory memberships",
+			zap.String("resource_id", userId),
+			zap.Int("removed", removedTerritories),
+			zap.Error(err))
+
+		return response(false), client.WithRateLimitAnnotations(ratelimitData), err
+	}
+
+	ratelimitData, err = s.client.SetUserActiveState(ctx, userId, false)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		l.Error("Failed to deactivate user after transferring ownership",
+			zap.String("resource_id", userId),
+			zap.Error(err))
+
+		return response(false), outputAnnotations, err
+	}
+
+	return response(true), outputAnnotations, nil
+}
@@ -0,0 +1,165 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-salesforce/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// TestUserLoginActions checks that freezing, unfreezing and unlocking a user
+// patch their UserLogin record. The fixtures server can't store
+// booleans in UserLogin, so this stands up a server that records the patch.
+func TestUserLoginActions(t *testing.T) {
+	ctx := context.Background()
+
+	var patchedPath string
+	var patchedBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPatch:
+			patchedPath = r.URL.Path
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &patchedBody)
+			w.WriteHeader(http.StatusNoContent)
+		case strings.Contains(r.URL.Query().Get("q"), "'0051X'"):
+			_, _ = w.Write([]byte(`{"totalSize":0,"done":true,"records":[]}`))
+		default:
+			_, _ = w.Write([]byte(`{"totalSize":1,"done":true,"records":[` +
+				`{"Id":"0Yw2X","UserId":"0052X","IsFrozen":false,"IsPasswordLocked":true}]}`))
+		}
+	}))
+	defer server.Close()
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Salesforce{client: salesforceClient}
+
+	t.Run("should freeze and unfreeze a user", func(t *testing.T) {
+		args, err := structpb.NewStruct(map[string]interface{}{"resource_id": "0052X"})
+		require.Nil(t, err)
+
+		response, _, err := c.freezeUser(ctx, args)
+		require.Nil(t, err)
+		require.True(t, response.Fields["success"].GetBoolValue())
+		require.True(t, response.Fields["is_frozen"].GetBoolValue())
+		require.True(t, strings.HasSuffix(patchedPath, "/sobjects/UserLogin/0Yw2X"))
+		require.Equal(t, true, patchedBody["IsFrozen"])
+
+		response, _, err = c.unfreezeUser(ctx, args)
+		require.Nil(t, err)
+		require.False(t, response.Fields["is_frozen"].GetBoolValue())
+		require.Equal(t, false, patchedBody["IsFrozen"])
+	})
+
+	t.Run("should fail for a user without a login", func(t *testing.T) {
+		args, err := structpb.NewStruct(map[string]interface{}{"resource_id": "0051X"})
+		require.Nil(t, err)
+
+		_, _, err = c.freezeUser(ctx, args)
+		require.NotNil(t, err)
+
+		_, _, err = c.unlockUser(ctx, args)
+		require.NotNil(t, err)
+	})
+
+	t.Run("should unlock a password-locked user", func(t *testing.T) {
+		patchedBody = nil
+		args, err := structpb.NewStruct(map[string]interface{}{"resource_id": "0052X"})
+		require.Nil(t, err)
+
+		response, _, err := c.unlockUser(ctx, args)
+		require.Nil(t, err)
+		require.True(t, response.Fields["success"].GetBoolValue())
+		require.False(t, response.Fields["is_password_locked"].GetBoolValue())
+		require.True(t, strings.HasSuffix(patchedPath, "/sobjects/UserLogin/0Yw2X"))
+		require.Equal(t, false, patchedBody["IsPasswordLocked"])
+	})
+}
+
+// TestDeprovisionUserPartialFailure checks that a failure partway through
+// still reports the records that were already handed to the successor.
+func TestDeprovisionUserPartialFailure(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPatch {
+			body, _ := io.ReadAll(r.Body)
+			if strings.Contains(string(body), `"Lead"`) {
+				_, _ = w.Write([]byte(`[{"id":"00Q1X","success":false,"errors":[` +
+					`{"statusCode":"UNABLE_TO_LOCK_ROW","message":"unable to obtain exclusive access"}]}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"id":"5001X","success":true,"errors":[]}]`))
+			return
+		}
+		id := "5001X"
+		if strings.Contains(r.URL.Query().Get("q"), "FROM Lead") {
+			id = "00Q1X"
+		}
+		_, _ = w.Write([]byte(`{"totalSize":1,"done":true,"records":[{"Id":"` + id + `"}]}`))
+	}))
+	defer server.Close()
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Salesforce{client: salesforceClient}
+
+	args, err := structpb.NewStruct(map[string]interface{}{
+		"resource_id":  "0051X",
+		"successor_id": "0052X",
+		"objects":      []interface{}{"Case", "Lead"},
+	})
+	require.Nil(t, err)
+
+	response, _, err := c.deprovisionUser(ctx, args)
+	require.ErrorContains(t, err, "UNABLE_TO_LOCK_ROW")
+	require.NotNil(t, response)
+	require.False(t, response.Fields["success"].GetBoolValue())
+	require.Equal(t, float64(1), response.Fields["transferred_records"].GetNumberValue())
+	transferred := response.Fields["transferred"].GetStructValue().GetFields()
+	require.Equal(t, "1", transferred["Case"].GetStringValue())
+	require.Equal(t, "0", transferred["Lead"].GetStringValue())
+}
+
+func TestDeprovisionUserRejectsInvalidObjects(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "unexpected request", "%s %s", r.Method, r.URL)
+	}))
+	defer server.Close()
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Salesforce{client: salesforceClient}
+
+	for _, object := range []string{"Case WHERE Id != null", "Case__c, Id", "1Case", "Lead;"} {
+		args, err := structpb.NewStruct(map[string]interface{}{
+			"resource_id":  "0051X",
+			"successor_id": "0052X",
+			"objects":      []interface{}{"Case", object},
+		})
+		require.Nil(t, err)
+
+		response, _, err := c.deprovisionUser(ctx, args)
+		require.ErrorContains(t, err, "invalid Salesforce object name")
+		require.Nil(t, response)
+	}
+}
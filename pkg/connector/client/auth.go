@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 
@@ -10,7 +11,10 @@ import (
 	"golang.org/x/oauth2/jwt"
 )
 
-const oauthTokenPath = "/services/oauth2/token" //nolint:gosec // false positive: this is an API path, not a credential
+const (
+	oauthAuthorizePath = "/services/oauth2/authorize"
+	oauthTokenPath     = "/services/oauth2/token" //nolint:gosec // false positive: this is an API path, not a credential
+)
 
 // NewJWTBearerTokenSource exchanges a signed JWT assertion for a Salesforce access token (RFC 7523).
 func NewJWTBearerTokenSource(ctx context.Context, clientID, subject, loginURL string, privateKey []byte) (oauth2.TokenSource, error) {
@@ -28,13 +32,17 @@ func NewJWTBearerTokenSource(ctx context.Context, clientID, subject, loginURL st
 		TokenURL:   u.JoinPath(oauthTokenPath).String(),
 		Audience:   u.String(),
 	}
-	ts := cfg.TokenSource(ctx)
+	// cfg.TokenSource caches its token, so build a new one for every exchange
+	// to be able to replace a token Salesforce has stopped accepting.
+	ts := tokenFunc(func() (*oauth2.Token, error) {
+		return cfg.TokenSource(ctx).Token()
+	})
 	// Validate credentials eagerly so errors surface at startup.
 	tok, err := ts.Token()
 	if err != nil {
 		return nil, fmt.Errorf("baton-salesforce: JWT bearer token exchange failed: %w", err)
 	}
-	return oauth2.ReuseTokenSource(tok, ts), nil
+	return newRefreshingTokenSource(tok, ts), nil
 }
 
 // NewClientCredentialsTokenSource obtains a Salesforce access token via the OAuth 2.0 client credentials flow.
@@ -52,11 +60,76 @@ func NewClientCredentialsTokenSource(ctx context.Context, clientID, clientSecret
 		TokenURL:     u.JoinPath(oauthTokenPath).String(),
 		AuthStyle:    oauth2.AuthStyleInParams, // Salesforce expects credentials in the request body, not Basic Auth
 	}
-	ts := cfg.TokenSource(ctx)
+	// cfg.Token makes a new exchange on every call, unlike cfg.TokenSource.
+	ts := tokenFunc(func() (*oauth2.Token, error) {
+		return cfg.Token(ctx)
+	})
 	// Validate credentials eagerly so errors surface at startup.
 	tok, err := ts.Token()
 	if err != nil {
 		return nil, fmt.Errorf("baton-salesforce: client credentials token exchange failed: %w", err)
 	}
-	return oauth2.ReuseTokenSource(tok, ts), nil
+	return newRefreshingTokenSource(tok, ts), nil
+}
+
+// NewWebServerConfig returns the OAuth 2.0 Web Server flow configuration for
+// a Connected App or External Client App. The "refresh_token" scope is what
+// makes Salesforce issue a refresh token with the access token.
+func NewWebServerConfig(clientID, clientSecret, instanceURL, redirectURL string) (*oauth2.Config, error) {
+	if instanceURL == "" {
+		return nil, fmt.Errorf("baton-salesforce: instanceURL must not be empty")
+	}
+	u, err := url.Parse(instanceURL)
+	if err != nil {
+		return nil, fmt.Errorf("baton-salesforce: invalid instanceURL: %w", err)
+	}
+	return &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   u.JoinPath(oauthAuthorizePath).String(),
+			TokenURL:  u.JoinPath(oauthTokenPath).String(),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: redirectURL,
+		Scopes:      []string{"api", "refresh_token"},
+	}, nil
+}
+
+// ErrRefreshTokenRotated is returned when the app issues a new refresh token
+// on refresh. The connector can't persist it, so rotation must be off.
+var ErrRefreshTokenRotated = errors.New(
+	"baton-salesforce: the app rotated the refresh token, which the connector can't store; " +
+		"turn off refresh token rotation on the app and run oauth-login again",
+)
+
+// NewRefreshTokenTokenSource obtains Salesforce access tokens with a refresh token from the OAuth 2.0 Web Server flow.
+func NewRefreshTokenTokenSource(ctx context.Context, clientID, clientSecret, refreshToken, instanceURL string) (oauth2.TokenSource, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("baton-salesforce: refreshToken must not be empty")
+	}
+	cfg, err := NewWebServerConfig(clientID, clientSecret, instanceURL, "")
+	if err != nil {
+		return nil, err
+	}
+	// cfg.TokenSource caches its token, so build a new one for every refresh.
+	// With refresh token rotation, Salesforce hands back a new refresh token
+	// and invalidates the configured one, which the connector has nowhere to
+	// store. Fail now rather than on the next restart.
+	ts := tokenFunc(func() (*oauth2.Token, error) {
+		tok, err := cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+		if err != nil {
+			return nil, err
+		}
+		if tok.RefreshToken != refreshToken {
+			return nil, ErrRefreshTokenRotated
+		}
+		return tok, nil
+	})
+	// Validate credentials eagerly so errors surface at startup.
+	tok, err := ts.Token()
+	if err != nil {
+		return nil, fmt.Errorf("baton-salesforce: refresh token exchange failed: %w", err)
+	}
+	return newRefreshingTokenSource(tok, ts), nil
 }
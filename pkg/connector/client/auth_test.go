@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRefreshTokenTokenSource(t *testing.T) {
+	ctx := context.Background()
+
+	// tokenServer answers refresh requests, rotating the refresh token when
+	// rotate is set, as orgs with refresh token rotation do.
+	tokenServer := func(t *testing.T, rotate bool) (*httptest.Server, *[]string) {
+		var refreshTokens []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, oauthTokenPath, r.URL.Path)
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+			assert.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+			refreshTokens = append(refreshTokens, r.PostForm.Get("refresh_token"))
+			response := map[string]any{
+				"access_token": fmt.Sprintf("access-%d", len(refreshTokens)),
+				"token_type":   "Bearer",
+				"instance_url": "https://acme.my.salesforce.com",
+			}
+			if rotate {
+				response["refresh_token"] = fmt.Sprintf("refresh-%d", len(refreshTokens))
+			}
+			writeJSON(t, w, response)
+		}))
+		t.Cleanup(server.Close)
+		return server, &refreshTokens
+	}
+
+	t.Run("should reuse the refresh token", func(t *testing.T) {
+		server, refreshTokens := tokenServer(t, false)
+
+		tokenSource, err := NewRefreshTokenTokenSource(ctx, "client-id", "client-secret", "refresh-0", server.URL)
+		require.NoError(t, err)
+
+		token, err := tokenSource.Token()
+		require.NoError(t, err)
+		require.Equal(t, "access-1", token.AccessToken)
+		require.Equal(t, "https://acme.my.salesforce.com", token.Extra("instance_url"))
+
+		refreshing, ok := tokenSource.(*refreshingTokenSource)
+		require.True(t, ok)
+		refreshing.invalidate("access-1")
+		token, err = tokenSource.Token()
+		require.NoError(t, err)
+		require.Equal(t, "access-2", token.AccessToken)
+		require.Equal(t, []string{"refresh-0", "refresh-0"}, *refreshTokens)
+	})
+
+	t.Run("should fail when the app rotates refresh tokens", func(t *testing.T) {
+		server, _ := tokenServer(t, true)
+
+		_, err := NewRefreshTokenTokenSource(ctx, "client-id", "client-secret", "refresh-0", server.URL)
+		require.ErrorIs(t, err, ErrRefreshTokenRotated)
+	})
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// apiLimitWindow is the rolling window of the org's daily API request limit.
+	apiLimitWindow = 24 * time.Hour
+	// limitsPollInterval is how stale the usage estimate may get before the
+	// transport asks /limits for a fresh one.
+	limitsPollInterval = 5 * time.Minute
+	// overBudgetPollInterval replaces limitsPollInterval once the budget is
+	// used up, since no responses come back to move the estimate down.
+	overBudgetPollInterval = time.Minute
+	minBudgetBackoff       = time.Minute
+)
+
+// apiBudget caps how much of the org's daily API request limit the connector
+// uses, so a full sync leaves room for the other integrations sharing it. The
+// estimate comes from the Sforce-Limit-Info header of every response and from
+// polling /limits when no responses have arrived for a while.
+type apiBudget struct {
+	percent int64
+	now     func() time.Time
+
+	mu          sync.Mutex
+	used        int64
+	limit       int64
+	refreshedAt time.Time
+}
+
+func newAPIBudget(percent int) *apiBudget {
+	return &apiBudget{
+		percent: int64(percent),
+		now:     time.Now,
+	}
+}
+
+func (b *apiBudget) observe(used int64, limit int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used = used
+	b.limit = limit
+	b.refreshedAt = b.now()
+}
+
+func (b *apiBudget) allowedLocked() int64 {
+	return b.limit * b.percent / 100
+}
+
+// claimRefresh reports whether the estimate is stale enough to poll /limits.
+// Only one caller is told to poll per interval.
+func (b *apiBudget) claimRefresh() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.limit == 0 {
+		// Nothing is known about the org yet; the first response will say.
+		return false
+	}
+	interval := limitsPollInterval
+	if b.used >= b.allowedLocked() {
+		interval = overBudgetPollInterval
+	}
+	now := b.now()
+	if now.Sub(b.refreshedAt) < interval {
+		return false
+	}
+	b.refreshedAt = now
+	return true
+}
+
+// check returns a codes.Unavailable error carrying a RateLimitDescription
+// once usage reaches the budget. Its ResetAt is an estimate of when enough
+// calls will have aged out of the rolling window, assuming they were spread
+// evenly across it, and baton-sdk waits until then before trying again.
+func (b *apiBudget) check() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	allowed := b.allowedLocked()
+	if b.limit == 0 || b.used < allowed {
+		return nil
+	}
+
+	excess := b.used - allowed + 1
+	backoff := time.Duration(float64(apiLimitWindow) * float64(excess) / float64(b.used))
+	backoff = min(max(backoff, minBudgetBackoff), apiLimitWindow)
+
+	st, err := status.New(
+		codes.Unavailable,
+		fmt.Sprintf(
+			"baton-salesforce: used %d of %d daily API requests, over the %d%% budget",
+			b.used,
+			b.limit,
+			b.percent,
+		),
+	).WithDetails(&v2.RateLimitDescription{
+		Status:    v2.RateLimitDescription_STATUS_OVERLIMIT,
+		Limit:     allowed,
+		Remaining: 0,
+		ResetAt:   timestamppb.New(b.now().Add(backoff)),
+	})
+	if err != nil {
+		return err
+	}
+	return st.Err()
+}
+
+// pollLimits refreshes the budget from /limits with the credentials of the
+// request being sent. Failures only leave the estimate as it was.
+func (t *salesforceHttpTransport) pollLimits(request *http.Request) {
+	logger := ctxzap.Extract(request.Context())
+
+	u := *request.URL
+	u.Path = LimitsPath
+	u.RawQuery = ""
+	limitsRequest, err := http.NewRequestWithContext(request.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		logger.Warn("baton-salesforce: failed to build limits request", zap.Error(err))
+		return
+	}
+	limitsRequest.Header.Set("Authorization", request.Header.Get("Authorization"))
+	limitsRequest.Header.Set("Accept", "application/json")
+
+	response, err := t.base.RoundTrip(limitsRequest)
+	if err != nil {
+		logger.Warn("baton-salesforce: failed to poll API limits", zap.Error(err))
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		logger.Warn("baton-salesforce: failed to poll API limits", zap.Int("status_code", response.StatusCode))
+		return
+	}
+
+	var limits struct {
+		DailyApiRequests struct {
+			Max       int64 `json:"Max"`
+			Remaining int64 `json:"Remaining"`
+		} `json:"DailyApiRequests"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&limits); err != nil {
+		logger.Warn("baton-salesforce: failed to decode API limits", zap.Error(err))
+		return
+	}
+	daily := limits.DailyApiRequests
+	if daily.Max > 0 {
+		t.budget.observe(daily.Max-daily.Remaining, daily.Max)
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIBudget(t *testing.T) {
+	ctx := context.Background()
+
+	var limitsPolls int
+	var queries int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == LimitsPath {
+			limitsPolls++
+			writeJSON(t, w, map[string]any{
+				"DailyApiRequests": map[string]any{"Max": 15000, "Remaining": 12000},
+			})
+			return
+		}
+		queries++
+		w.Header().Set(RateLimitHeaderKey, "api-usage=4500/15000")
+		writeJSON(t, w, map[string]any{"totalSize": 0, "done": true, "records": []any{}})
+	}))
+	t.Cleanup(server.Close)
+
+	c := New(server.URL, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "mock-access-token"}), "", "", "")
+	c.SetAPIBudget(30)
+	require.NoError(t, c.Initialize(ctx))
+	now := time.Now()
+	c.salesforceTransport.budget.now = func() time.Time { return now }
+
+	t.Run("should refuse requests once the budget is used up", func(t *testing.T) {
+		_, _, _, err := c.query(ctx, NewQuery(TableNameUsers), "", PageSizeDefault)
+		require.NoError(t, err)
+
+		_, _, _, err = c.query(ctx, NewQuery(TableNameUsers).WhereEq("Id", "005A"), "", PageSizeDefault)
+		require.Error(t, err)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Equal(t, 1, queries)
+
+		st, _ := status.FromError(err)
+		require.Len(t, st.Details(), 1)
+		description, ok := st.Details()[0].(*v2.RateLimitDescription)
+		require.True(t, ok)
+		require.Equal(t, v2.RateLimitDescription_STATUS_OVERLIMIT, description.Status)
+		require.Equal(t, int64(4500), description.Limit)
+		// 1 call over a 4500 call budget ages out in about 24h/4500.
+		require.Equal(t, now.Add(minBudgetBackoff).Unix(), description.ResetAt.AsTime().Unix())
+	})
+
+	t.Run("should poll limits and resume once usage drops", func(t *testing.T) {
+		now = now.Add(overBudgetPollInterval)
+
+		_, _, _, err := c.query(ctx, NewQuery(TableNameUsers).WhereEq("Id", "005B"), "", PageSizeDefault)
+		require.NoError(t, err)
+		require.Equal(t, 1, limitsPolls)
+		require.Equal(t, 2, queries)
+	})
+}
+
+func TestAPIBudgetBackoff(t *testing.T) {
+	now := time.Now()
+	budget := newAPIBudget(50)
+	budget.now = func() time.Time { return now }
+	budget.observe(10000, 10000)
+
+	st, _ := status.FromError(budget.check())
+	description, ok := st.Details()[0].(*v2.RateLimitDescription)
+	require.True(t, ok)
+	// Half of the calls in the window have to age out: about 12 hours.
+	require.InDelta(t, 12*time.Hour.Seconds(), description.ResetAt.AsTime().Sub(now).Seconds(), 60)
+
+	budget.observe(4999, 10000)
+	require.NoError(t, budget.check())
+}
@@ -0,0 +1,281 @@
+package client
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/conductorone/simpleforce"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	BulkQueryJobsPath = "/services/data/v64.0/jobs/query"
+
+	// bulkPageTokenPrefix marks a page token as a Bulk API 2.0 result locator
+	// rather than a REST nextRecordsUrl. The token carries the job ID, so a
+	// restarted sync resumes reading the same job: Salesforce keeps query job
+	// results for seven days.
+	bulkPageTokenPrefix = "bulk:"
+	bulkLocatorHeader   = "Sforce-Locator"
+	bulkLocatorDone     = "null"
+	bulkQueryMaxRecords = 10000
+
+	bulkJobStateComplete = "JobComplete"
+	bulkJobStateFailed   = "Failed"
+	bulkJobStateAborted  = "Aborted"
+
+	bulkQueryPollTimeout     = 30 * time.Minute
+	bulkQueryMaxPollInterval = 30 * time.Second
+)
+
+// bulkQueryInitialPollInterval is a var so tests can shorten it.
+var bulkQueryInitialPollInterval = time.Second
+
+type bulkQueryJob struct {
+	ID           string `json:"id"`
+	State        string `json:"state"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// queryLarge behaves like query, but when bulk queries are enabled and
+// Salesforce reports more than bulkQueryThreshold matching rows on the first
+// REST page, it discards that page and runs the query as a Bulk API 2.0 job
+// instead. A bulk job costs a handful of API calls no matter how many rows it
+// returns, whereas REST pages cap out at 2,000 records per call. Later pages
+// stay on whichever API the first page picked, based on the token's shape.
+func (c *SalesforceClient) queryLarge(
+	ctx context.Context,
+	query *SalesforceQuery,
+	pageToken string,
+	pageSize int,
+) (
+	[]simpleforce.SObject,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	if strings.HasPrefix(pageToken, bulkPageTokenPrefix) {
+		return c.bulkQueryPage(ctx, pageToken)
+	}
+	if c.bulkQueryThreshold <= 0 || pageToken != "" {
+		return c.query(ctx, query, pageToken, pageSize)
+	}
+
+	// Render the SOQL before the REST call, which appends ORDER BY Id to the
+	// shared builder. A bulk job's result set is fixed once it completes, so
+	// it doesn't need the ordering, and leaving it out lets Salesforce chunk
+	// the job by primary key.
+	soql := query.String()
+	records, ratelimitData, err := c.queryResult(ctx, query, "", pageSize)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+	if records.TotalSize <= c.bulkQueryThreshold {
+		return records.Records, nextRecordsToken(records), ratelimitData, nil
+	}
+
+	ctxzap.Extract(ctx).Info(
+		"salesforce-client: query exceeds bulk threshold, switching to Bulk API 2.0",
+		zap.Int("total_size", records.TotalSize),
+		zap.Int("threshold", c.bulkQueryThreshold),
+	)
+	jobID, ratelimitData, err := c.runBulkQueryJob(ctx, soql)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+	return c.bulkQueryPage(ctx, bulkPageToken(jobID, ""))
+}
+
+// runBulkQueryJob creates a Bulk API 2.0 query job and blocks until Salesforce
+// finishes processing it.
+func (c *SalesforceClient) runBulkQueryJob(
+	ctx context.Context,
+	soql string,
+) (
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	job := &bulkQueryJob{}
+	_, ratelimitData, err := c.restRequest(
+		ctx,
+		http.MethodPost,
+		BulkQueryJobsPath,
+		map[string]string{
+			"operation": "query",
+			"query":     soql,
+		},
+		uhttp.WithJSONResponse(job),
+	)
+	if err != nil {
+		return "", ratelimitData, fmt.Errorf("baton-salesforce: failed to create bulk query job: %w", err)
+	}
+	if job.ID == "" {
+		return "", ratelimitData, fmt.Errorf("baton-salesforce: bulk query job response has no id")
+	}
+
+	ratelimitData, err = c.waitForBulkQueryJob(ctx, job.ID)
+	if err != nil {
+		return "", ratelimitData, err
+	}
+	return job.ID, ratelimitData, nil
+}
+
+func (c *SalesforceClient) waitForBulkQueryJob(
+	ctx context.Context,
+	jobID string,
+) (*v2.RateLimitDescription, error) {
+	logger := ctxzap.Extract(ctx)
+	ctx, cancel := context.WithTimeout(ctx, bulkQueryPollTimeout)
+	defer cancel()
+
+	interval := bulkQueryInitialPollInterval
+	for {
+		job := &bulkQueryJob{}
+		_, ratelimitData, err := c.restRequest(
+			ctx,
+			http.MethodGet,
+			BulkQueryJobsPath+"/"+url.PathEscape(jobID),
+			nil,
+			uhttp.WithJSONResponse(job),
+		)
+		if err != nil {
+			return ratelimitData, fmt.Errorf("baton-salesforce: failed to get bulk query job %s: %w", jobID, err)
+		}
+
+		switch job.State {
+		case bulkJobStateComplete:
+			return ratelimitData, nil
+		case bulkJobStateFailed, bulkJobStateAborted:
+			return ratelimitData, fmt.Errorf(
+				"baton-salesforce: bulk query job %s ended in state %s: %s",
+				jobID,
+				job.State,
+				job.ErrorMessage,
+			)
+		}
+
+		logger.Debug(
+			"salesforce-client: waiting for bulk query job",
+			zap.String("job_id", jobID),
+			zap.String("state", job.State),
+			zap.Duration("interval", interval),
+		)
+		select {
+		case <-ctx.Done():
+			return ratelimitData, fmt.Errorf("baton-salesforce: gave up waiting for bulk query job %s: %w", jobID, ctx.Err())
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, bulkQueryMaxPollInterval)
+	}
+}
+
+// bulkQueryPage reads one page of CSV results from a completed bulk query job.
+func (c *SalesforceClient) bulkQueryPage(
+	ctx context.Context,
+	pageToken string,
+) (
+	[]simpleforce.SObject,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	jobID, locator, err := parseBulkPageToken(pageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	params := url.Values{"maxRecords": {strconv.Itoa(bulkQueryMaxRecords)}}
+	if locator != "" {
+		params.Set("locator", locator)
+	}
+	response, ratelimitData, err := c.restRequest(
+		ctx,
+		http.MethodGet,
+		BulkQueryJobsPath+"/"+url.PathEscape(jobID)+"/results?"+params.Encode(),
+		nil,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, fmt.Errorf("baton-salesforce: failed to get bulk query job %s results: %w", jobID, err)
+	}
+
+	records, err := decodeBulkQueryResults(response.Body)
+	if err != nil {
+		return nil, "", ratelimitData, fmt.Errorf("baton-salesforce: failed to decode bulk query job %s results: %w", jobID, err)
+	}
+
+	nextToken := ""
+	if next := response.Header.Get(bulkLocatorHeader); next != "" && next != bulkLocatorDone {
+		nextToken = bulkPageToken(jobID, next)
+	}
+	return records, nextToken, ratelimitData, nil
+}
+
+func bulkPageToken(jobID string, locator string) string {
+	return bulkPageTokenPrefix + jobID + ":" + locator
+}
+
+func parseBulkPageToken(pageToken string) (string, string, error) {
+	jobID, locator, found := strings.Cut(strings.TrimPrefix(pageToken, bulkPageTokenPrefix), ":")
+	if !found || jobID == "" {
+		return "", "", fmt.Errorf("baton-salesforce: invalid bulk query page token %q", pageToken)
+	}
+	return jobID, locator, nil
+}
+
+// decodeBulkQueryResults turns a Bulk API 2.0 CSV result page into the same
+// record shape the REST query endpoint returns, so the existing model mapping
+// works unchanged. Relationship columns such as
+// "Profile.UserLicense.LicenseDefinitionKey" become nested maps.
+func decodeBulkQueryResults(body io.Reader) ([]simpleforce.SObject, error) {
+	reader := csv.NewReader(body)
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return []simpleforce.SObject{}, nil
+		}
+		return nil, err
+	}
+
+	records := make([]simpleforce.SObject, 0)
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := simpleforce.SObject{}
+		for i, column := range header {
+			setBulkField(record, strings.Split(column, "."), row[i])
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func setBulkField(record map[string]interface{}, path []string, value string) {
+	if len(path) == 1 {
+		record[path[0]] = value
+		return
+	}
+	nested, ok := record[path[0]].(map[string]interface{})
+	if !ok {
+		nested = map[string]interface{}{}
+		record[path[0]] = nested
+	}
+	setBulkField(nested, path[1:], value)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBulkQueryResults(t *testing.T) {
+	body := strings.Join([]string{
+		`"Id","IsActive","LastLoginDate","Profile.UserLicense.LicenseDefinitionKey","Username"`,
+		`"0051X","true","2025-03-26T16:43:31.000Z","SFDC","someone@example.com"`,
+		`"0052X","false","","",""`,
+	}, "\n")
+
+	records, err := decodeBulkQueryResults(strings.NewReader(body))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	require.Equal(t, "0051X", records[0].ID())
+	require.Equal(t, "someone@example.com", records[0].StringField("Username"))
+	require.Equal(t, "SFDC", licenseDefinitionKey(records[0]))
+	isActive, err := getIsActive(records[0])
+	require.NoError(t, err)
+	require.True(t, isActive)
+	lastLogin, err := parseSalesforceDatetime(records[0].StringField("LastLoginDate"))
+	require.NoError(t, err)
+	require.NotNil(t, lastLogin)
+
+	isActive, err = getIsActive(records[1])
+	require.NoError(t, err)
+	require.False(t, isActive)
+	require.Empty(t, licenseDefinitionKey(records[1]))
+}
+
+func TestDecodeBulkQueryResultsEmpty(t *testing.T) {
+	records, err := decodeBulkQueryResults(strings.NewReader(""))
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestBulkPageToken(t *testing.T) {
+	jobID, locator, err := parseBulkPageToken(bulkPageToken("750R0000000zlh9IAA", "MTAwMDA"))
+	require.NoError(t, err)
+	require.Equal(t, "750R0000000zlh9IAA", jobID)
+	require.Equal(t, "MTAwMDA", locator)
+
+	jobID, locator, err = parseBulkPageToken(bulkPageToken("750R0000000zlh9IAA", ""))
+	require.NoError(t, err)
+	require.Equal(t, "750R0000000zlh9IAA", jobID)
+	require.Empty(t, locator)
+
+	_, _, err = parseBulkPageToken("bulk:")
+	require.Error(t, err)
+}
@@ -0,0 +1,353 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/simpleforce"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	systemModstampField = "SystemModstamp"
+	createdDateField    = "CreatedDate"
+	loginTimeField      = "LoginTime"
+
+	// ChangesPageSizeMax is the most rows a single change query asks for. It
+	// matches the largest page the REST query endpoint returns in one call.
+	ChangesPageSizeMax = 2000
+
+	// usernameBatchSize keeps "Username IN (...)" and the other name filters
+	// well under the SOQL length limit.
+	usernameBatchSize = 200
+)
+
+// ChangeCursor marks where a change query left off: the timestamp and Id of
+// the last record it returned.
+type ChangeCursor struct {
+	Time time.Time `json:"time"`
+	ID   string    `json:"id,omitempty"`
+}
+
+// precedes reports whether a record with the given timestamp and Id comes
+// after the cursor in (timestamp, Id) order.
+func (c ChangeCursor) precedes(t time.Time, id string) bool {
+	if t.Equal(c.Time) {
+		return id > c.ID
+	}
+	return t.After(c.Time)
+}
+
+type changedRecord struct {
+	record simpleforce.SObject
+	at     time.Time
+}
+
+// queryChanges returns up to limit records whose timestamp field is at or
+// after the cursor, skipping the ones the cursor already covers, along with
+// the cursor to resume from and whether there may be more rows.
+//
+// SOQL datetime literals only have second precision while the timestamps
+// themselves have milliseconds, so the query starts at the cursor's second and
+// the rows of that second that were already returned get filtered out here.
+func (c *SalesforceClient) queryChanges(
+	ctx context.Context,
+	query *SalesforceQuery,
+	field string,
+	cursor ChangeCursor,
+	limit int,
+) (
+	[]changedRecord,
+	ChangeCursor,
+	bool,
+	*v2.RateLimitDescription,
+	error,
+) {
+	limit = min(max(limit, 1), ChangesPageSizeMax)
+	since := cursor.Time.UTC().Truncate(time.Second)
+	query = query.
+		WhereDatetimeGTE(field, since).
+		OrderBy(field).
+		Limit(limit)
+	records, _, ratelimitData, err := c.query(ctx, query, "", limit)
+	if err != nil {
+		return nil, cursor, false, ratelimitData, err
+	}
+
+	changes := make([]changedRecord, 0, len(records))
+	next := cursor
+	for _, record := range records {
+		at, err := parseSalesforceDatetime(record.StringField(field))
+		if err != nil {
+			return nil, cursor, false, ratelimitData, err
+		}
+		if at == nil || !cursor.precedes(*at, record.ID()) {
+			continue
+		}
+		changes = append(changes, changedRecord{record: record, at: *at})
+		next = ChangeCursor{Time: *at, ID: record.ID()}
+	}
+
+	hasMore := len(records) >= limit
+	if hasMore && len(changes) == 0 {
+		// A full page that is entirely behind the cursor means more than limit
+		// rows share the cursor's second, and re-reading it would never make
+		// progress. Skip ahead to the next second.
+		next = ChangeCursor{Time: since.Add(time.Second)}
+		ctxzap.Extract(ctx).Warn(
+			"salesforce-client: too many changes within one second, skipping ahead",
+			zap.String("field", field),
+			zap.Time("since", since),
+			zap.Int("limit", limit),
+		)
+	}
+	return changes, next, hasMore, ratelimitData, nil
+}
+
+// GetPermissionSetAssignmentChanges lists permission set and permission set
+// group assignments created or modified after the cursor, oldest first.
+// Deleted assignments don't show up here.
+func (c *SalesforceClient) GetPermissionSetAssignmentChanges(
+	ctx context.Context,
+	cursor ChangeCursor,
+	limit int,
+) (
+	[]*PermissionSetAssignment,
+	ChangeCursor,
+	bool,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(
+		TableNamePermissionAssignments,
+		"PermissionSetId",
+		"PermissionSetGroupId",
+		"AssigneeId",
+		systemModstampField,
+	)
+	changes, next, hasMore, ratelimitData, err := c.queryChanges(ctx, query, systemModstampField, cursor, limit)
+	if err != nil {
+		return nil, cursor, false, ratelimitData, err
+	}
+
+	assignments := make([]*PermissionSetAssignment, 0, len(changes))
+	for _, change := range changes {
+		assignments = append(assignments, &PermissionSetAssignment{
+			ID:                   change.record.ID(),
+			UserID:               change.record.StringField("AssigneeId"),
+			PermissionSetID:      change.record.StringField("PermissionSetId"),
+			PermissionSetGroupID: change.record.StringField("PermissionSetGroupId"),
+			ModifiedAt:           change.at,
+		})
+	}
+	return assignments, next, hasMore, ratelimitData, nil
+}
+
+// GetGroupMembershipChanges lists group memberships created or modified after
+// the cursor, oldest first. Deleted memberships don't show up here.
+func (c *SalesforceClient) GetGroupMembershipChanges(
+	ctx context.Context,
+	cursor ChangeCursor,
+	limit int,
+) (
+	[]*SalesforceGroupMembership,
+	ChangeCursor,
+	bool,
+	*v2.RateLimitDescription,
+	error,
+) {
+	logger := ctxzap.Extract(ctx)
+	query := NewQuery(
+		TableNameGroupMemberships,
+		"GroupId",
+		"UserOrGroupId",
+		systemModstampField,
+	)
+	changes, next, hasMore, ratelimitData, err := c.queryChanges(ctx, query, systemModstampField, cursor, limit)
+	if err != nil {
+		return nil, cursor, false, ratelimitData, err
+	}
+
+	memberships := make([]*SalesforceGroupMembership, 0, len(changes))
+	for _, change := range changes {
+		isGroup, err := getIsGroup(change.record)
+		if err != nil {
+			logger.Debug(
+				"salesforce-client: skipping record",
+				zap.Error(err),
+			)
+			continue
+		}
+		memberships = append(memberships, &SalesforceGroupMembership{
+			ID:          change.record.ID(),
+			GroupID:     change.record.StringField("GroupId"),
+			PrincipalID: change.record.StringField("UserOrGroupId"),
+			IsGroup:     isGroup,
+			ModifiedAt:  change.at,
+		})
+	}
+	return memberships, next, hasMore, ratelimitData, nil
+}
+
+// GetSetupAuditTrail lists Setup Audit Trail entries recorded after the
+// cursor, oldest first. Reading the audit trail needs the "View Setup and
+// Configuration" permission.
+func (c *SalesforceClient) GetSetupAuditTrail(
+	ctx context.Context,
+	cursor ChangeCursor,
+	limit int,
+) (
+	[]*SetupAuditTrailEntry,
+	ChangeCursor,
+	bool,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNameSetupAuditTrail)
+	changes, next, hasMore, ratelimitData, err := c.queryChanges(ctx, query, createdDateField, cursor, limit)
+	if err != nil {
+		return nil, cursor, false, ratelimitData, err
+	}
+
+	entries := make([]*SetupAuditTrailEntry, 0, len(changes))
+	for _, change := range changes {
+		entries = append(entries, &SetupAuditTrailEntry{
+			ID:           change.record.ID(),
+			Action:       change.record.StringField("Action"),
+			Section:      change.record.StringField("Section"),
+			Display:      change.record.StringField("Display"),
+			CreatedByID:  change.record.StringField("CreatedById"),
+			DelegateUser: change.record.StringField("DelegateUser"),
+			CreatedDate:  change.at,
+		})
+	}
+	return entries, next, hasMore, ratelimitData, nil
+}
+
+// GetLoginHistory lists login attempts, successful or not, made after the
+// cursor, oldest first.
+func (c *SalesforceClient) GetLoginHistory(
+	ctx context.Context,
+	cursor ChangeCursor,
+	limit int,
+) (
+	[]*LoginHistoryEntry,
+	ChangeCursor,
+	bool,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNameLoginHistory)
+	changes, next, hasMore, ratelimitData, err := c.queryChanges(ctx, query, loginTimeField, cursor, limit)
+	if err != nil {
+		return nil, cursor, false, ratelimitData, err
+	}
+
+	logins := make([]*LoginHistoryEntry, 0, len(changes))
+	for _, change := range changes {
+		logins = append(logins, &LoginHistoryEntry{
+			ID:          change.record.ID(),
+			UserID:      change.record.StringField("UserId"),
+			LoginTime:   change.at,
+			LoginType:   change.record.StringField("LoginType"),
+			LoginURL:    change.record.StringField("LoginUrl"),
+			SourceIP:    change.record.StringField("SourceIp"),
+			Status:      change.record.StringField("Status"),
+			Application: change.record.StringField("Application"),
+			Browser:     change.record.StringField("Browser"),
+			Platform:    change.record.StringField("Platform"),
+		})
+	}
+	return logins, next, hasMore, ratelimitData, nil
+}
+
+// GetUsersByUsername looks users up by username, regardless of user type or
+// active state. Usernames that don't match a user are left out.
+func (c *SalesforceClient) GetUsersByUsername(
+	ctx context.Context,
+	usernames []string,
+) (
+	[]*SalesforceUser,
+	*v2.RateLimitDescription,
+	error,
+) {
+	users := make([]*SalesforceUser, 0, len(usernames))
+	var ratelimitData *v2.RateLimitDescription
+	for start := 0; start < len(usernames); start += usernameBatchSize {
+		batch := usernames[start:min(start+usernameBatchSize, len(usernames))]
+		query := NewQuery(TableNameUsers).WhereIn("Username", batch...)
+		pageToken := ""
+		for {
+			var records []simpleforce.SObject
+			var err error
+			records, pageToken, ratelimitData, err = c.query(ctx, query, pageToken, len(batch))
+			if err != nil {
+				return nil, ratelimitData, fmt.Errorf("baton-salesforce: failed to get users by username: %w", err)
+			}
+			for _, record := range records {
+				isActive, err := getIsActive(record)
+				if err != nil {
+					return nil, ratelimitData, err
+				}
+				users = append(users, &SalesforceUser{
+					ID:         record.ID(),
+					Username:   record.StringField("Username"),
+					Email:      record.StringField("Email"),
+					FirstName:  record.StringField("FirstName"),
+					LastName:   record.StringField("LastName"),
+					UserType:   record.StringField("UserType"),
+					ProfileID:  record.StringField("ProfileId"),
+					UserRoleID: record.StringField("UserRoleId"),
+					IsActive:   isActive,
+				})
+			}
+			if pageToken == "" {
+				break
+			}
+		}
+	}
+	return users, ratelimitData, nil
+}
+
+// GetIDsByName looks up the Ids of the records in table whose field matches
+// one of names, keyed by the lowercased name. SOQL compares strings without
+// regard to case, so a name can come back with different casing than asked.
+// Names aren't unique on every object, so a name can map to several Ids.
+func (c *SalesforceClient) GetIDsByName(
+	ctx context.Context,
+	table string,
+	field string,
+	names []string,
+) (
+	map[string][]string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	ids := make(map[string][]string, len(names))
+	var ratelimitData *v2.RateLimitDescription
+	for batch := range slices.Chunk(names, usernameBatchSize) {
+		query := NewQuery(table, field).WhereIn(field, batch...)
+		pageToken := ""
+		for {
+			var records []simpleforce.SObject
+			var err error
+			records, pageToken, ratelimitData, err = c.query(ctx, query, pageToken, len(batch))
+			if err != nil {
+				return nil, ratelimitData, fmt.Errorf("baton-salesforce: failed to get %s records by %s: %w", table, field, err)
+			}
+			for _, record := range records {
+				name := strings.ToLower(record.StringField(field))
+				ids[name] = append(ids[name], record.ID())
+			}
+			if pageToken == "" {
+				break
+			}
+		}
+	}
+	return ids, ratelimitData, nil
+}
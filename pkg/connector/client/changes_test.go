@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPermissionSetAssignmentChanges(t *testing.T) {
+	ctx := context.Background()
+	cursor := ChangeCursor{
+		Time: time.Date(2025, 3, 26, 16, 43, 31, 500_000_000, time.UTC),
+		ID:   "0PaB",
+	}
+
+	t.Run("should skip rows the cursor already covers", func(t *testing.T) {
+		var soql string
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			soql = r.URL.Query().Get("q")
+			writeJSON(t, w, map[string]any{
+				"totalSize": 3,
+				"done":      true,
+				"records": []map[string]any{
+					{"Id": "0PaA", "AssigneeId": "005A", "SystemModstamp": "2025-03-26T16:43:31.500+0000"},
+					{"Id": "0PaB", "AssigneeId": "005B", "SystemModstamp": "2025-03-26T16:43:31.500+0000"},
+					{"Id": "0PaC", "AssigneeId": "005C", "SystemModstamp": "2025-03-26T16:43:31.500+0000"},
+				},
+			})
+		})
+
+		assignments, next, hasMore, _, err := c.GetPermissionSetAssignmentChanges(ctx, cursor, 10)
+		require.NoError(t, err)
+		require.Contains(t, soql, "SystemModstamp >= 2025-03-26T16:43:31Z")
+		require.Contains(t, soql, "ORDER BY SystemModstamp, Id")
+		require.Len(t, assignments, 1)
+		require.Equal(t, "005C", assignments[0].UserID)
+		require.Equal(t, "0PaC", next.ID)
+		require.False(t, hasMore)
+	})
+
+	t.Run("should move past a second that holds more than a page", func(t *testing.T) {
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, map[string]any{
+				"totalSize": 2,
+				"done":      true,
+				"records": []map[string]any{
+					{"Id": "0PaA", "AssigneeId": "005A", "SystemModstamp": "2025-03-26T16:43:31.100+0000"},
+					{"Id": "0PaB", "AssigneeId": "005B", "SystemModstamp": "2025-03-26T16:43:31.500+0000"},
+				},
+			})
+		})
+
+		assignments, next, hasMore, _, err := c.GetPermissionSetAssignmentChanges(ctx, cursor, 2)
+		require.NoError(t, err)
+		require.Empty(t, assignments)
+		require.True(t, hasMore)
+		require.Equal(t, ChangeCursor{Time: time.Date(2025, 3, 26, 16, 43, 32, 0, time.UTC)}, next)
+	})
+}
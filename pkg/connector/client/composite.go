@@ -0,0 +1,247 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/conductorone/simpleforce"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// sObject Collections are for writes that touch many records in one go, such
+// as revoking every OAuth token a user holds for an app or handing a user's
+// records to a successor. A territory role grant is already a single create or
+// update, and CreateAccount only creates the user: the SDK sends each of the
+// new account's permission set assignments as its own Grant call, so there is
+// nothing to combine there.
+const (
+	CompositeSObjectsPath = "/services/data/v64.0/composite/sobjects"
+
+	// compositeSObjectsMaxRecords is the sObject Collections per-request cap.
+	compositeSObjectsMaxRecords = 200
+
+	collectionErrorRolledBack = "ALL_OR_NONE_OPERATION_ROLLED_BACK"
+)
+
+// SObjectRecord is one record in an sObject Collections write. ID is required
+// for updates and ignored for creates.
+type SObjectRecord struct {
+	Type   string
+	ID     string
+	Fields map[string]interface{}
+}
+
+func (r *SObjectRecord) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(r.Fields)+2)
+	for key, value := range r.Fields {
+		out[key] = value
+	}
+	out["attributes"] = map[string]string{"type": r.Type}
+	if r.ID != "" {
+		out[SalesforcePK] = r.ID
+	}
+	return json.Marshal(out)
+}
+
+type CollectionError struct {
+	StatusCode string   `json:"statusCode"`
+	Message    string   `json:"message"`
+	Fields     []string `json:"fields"`
+}
+
+// CollectionResult is the per-record outcome of an sObject Collections call.
+// Results come back in the same order as the submitted records.
+type CollectionResult struct {
+	ID      string            `json:"id"`
+	Success bool              `json:"success"`
+	Errors  []CollectionError `json:"errors"`
+}
+
+// Err converts a failed result into a classified error, so callers can match
+// it against the same sentinels as the single-record helpers.
+func (r *CollectionResult) Err() error {
+	if r.Success {
+		return nil
+	}
+	if len(r.Errors) == 0 {
+		return fmt.Errorf("baton-salesforce: record %s failed without error details", r.ID)
+	}
+
+	first := r.Errors[0]
+	sfErr := simpleforce.SalesforceError{
+		Message: fmt.Sprintf(
+			"[simpleforce] Error. http code: %v Error Message:  %v Error Code: %v",
+			http.StatusOK,
+			first.Message,
+			first.StatusCode,
+		),
+		HttpCode:     http.StatusOK,
+		ErrorCode:    first.StatusCode,
+		ErrorMessage: first.Message,
+	}
+	return classifyError(sfErr)
+}
+
+func (r *CollectionResult) rolledBack() bool {
+	return !r.Success && len(r.Errors) > 0 && r.Errors[0].StatusCode == collectionErrorRolledBack
+}
+
+// collectionResultsError joins the errors of every failed record. When
+// allOrNone rolled the request back, the records that only failed because of
+// the rollback are left out so the error points at the record that caused it.
+func collectionResultsError(results []*CollectionResult) error {
+	var errs []error
+	var rolledBack []error
+	for i, result := range results {
+		err := result.Err()
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("record %d: %w", i, err)
+		if result.rolledBack() {
+			rolledBack = append(rolledBack, err)
+			continue
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		errs = rolledBack
+	}
+	return errors.Join(errs...)
+}
+
+// CreateObjects creates up to 200 records, possibly of different types, in a
+// single sObject Collections request. With allOrNone set, Salesforce rolls the
+// whole request back if any record fails. The returned results line up with
+// records; the error joins every per-record failure.
+func (c *SalesforceClient) CreateObjects(
+	ctx context.Context,
+	records []*SObjectRecord,
+	allOrNone bool,
+) (
+	[]*CollectionResult,
+	*v2.RateLimitDescription,
+	error,
+) {
+	return c.writeCollection(ctx, http.MethodPost, records, allOrNone)
+}
+
+// UpdateObjects updates up to 200 records in a single sObject Collections
+// request. Every record must have an ID.
+func (c *SalesforceClient) UpdateObjects(
+	ctx context.Context,
+	records []*SObjectRecord,
+	allOrNone bool,
+) (
+	[]*CollectionResult,
+	*v2.RateLimitDescription,
+	error,
+) {
+	for i, record := range records {
+		if record.ID == "" {
+			return nil, nil, fmt.Errorf("baton-salesforce: record %d has no id to update", i)
+		}
+	}
+	return c.writeCollection(ctx, http.MethodPatch, records, allOrNone)
+}
+
+// DeleteObjects deletes up to 200 records by ID in a single sObject
+// Collections request. The records don't need to share a type.
+func (c *SalesforceClient) DeleteObjects(
+	ctx context.Context,
+	ids []string,
+	allOrNone bool,
+) (
+	[]*CollectionResult,
+	*v2.RateLimitDescription,
+	error,
+) {
+	if len(ids) == 0 {
+		return []*CollectionResult{}, nil, nil
+	}
+	if len(ids) > compositeSObjectsMaxRecords {
+		return nil, nil, fmt.Errorf(
+			"baton-salesforce: cannot delete %d records in one request (max %d)",
+			len(ids),
+			compositeSObjectsMaxRecords,
+		)
+	}
+
+	params := url.Values{
+		"ids":       {strings.Join(ids, ",")},
+		"allOrNone": {strconv.FormatBool(allOrNone)},
+	}
+	results := make([]*CollectionResult, 0, len(ids))
+	_, ratelimitData, err := c.restRequest(
+		ctx,
+		http.MethodDelete,
+		CompositeSObjectsPath+"?"+params.Encode(),
+		nil,
+		uhttp.WithJSONResponse(&results),
+	)
+	if err != nil {
+		return nil, ratelimitData, fmt.Errorf("baton-salesforce: failed to delete records: %w", err)
+	}
+	return results, ratelimitData, collectionResultsError(results)
+}
+
+func (c *SalesforceClient) writeCollection(
+	ctx context.Context,
+	method string,
+	records []*SObjectRecord,
+	allOrNone bool,
+) (
+	[]*CollectionResult,
+	*v2.RateLimitDescription,
+	error,
+) {
+	if len(records) == 0 {
+		return []*CollectionResult{}, nil, nil
+	}
+	if len(records) > compositeSObjectsMaxRecords {
+		return nil, nil, fmt.Errorf(
+			"baton-salesforce: cannot write %d records in one request (max %d)",
+			len(records),
+			compositeSObjectsMaxRecords,
+		)
+	}
+
+	ctxzap.Extract(ctx).Debug(
+		"salesforce-client: writing sObject collection",
+		zap.String("method", method),
+		zap.Int("count", len(records)),
+		zap.Bool("all_or_none", allOrNone),
+	)
+
+	results := make([]*CollectionResult, 0, len(records))
+	_, ratelimitData, err := c.restRequest(
+		ctx,
+		method,
+		CompositeSObjectsPath,
+		map[string]interface{}{
+			"allOrNone": allOrNone,
+			"records":   records,
+		},
+		uhttp.WithJSONResponse(&results),
+	)
+	if err != nil {
+		return nil, ratelimitData, fmt.Errorf("baton-salesforce: failed to write records: %w", err)
+	}
+	if len(results) != len(records) {
+		return results, ratelimitData, fmt.Errorf(
+			"baton-salesforce: expected %d collection results, got %d",
+			len(records),
+			len(results),
+		)
+	}
+	return results, ratelimitData, collectionResultsError(results)
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func newTestClient(ctx context.Context, t *testing.T, handler http.HandlerFunc) *SalesforceClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := New(server.URL, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "mock-access-token"}), "", "", "")
+	require.NoError(t, c.Initialize(ctx))
+	return c
+}
+
+// writeJSON runs on the server's goroutine, so it reports failures with
+// assert: require's FailNow must only be called from the test goroutine.
+func writeJSON(t *testing.T, w http.ResponseWriter, v any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	assert.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func TestCreateObjects(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should send all records in one request", func(t *testing.T) {
+		var method, path string
+		var body []byte
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			var err error
+			body, err = io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			writeJSON(t, w, []map[string]any{
+				{"id": "0PaA", "success": true, "errors": []any{}},
+				{"id": "00GA", "success": true, "errors": []any{}},
+			})
+		})
+
+		results, _, err := c.CreateObjects(ctx, []*SObjectRecord{
+			{Type: TableNamePermissionAssignments, Fields: map[string]interface{}{"AssigneeId": "005A", "PermissionSetId": "0PSA"}},
+			{Type: TableNameGroupMemberships, Fields: map[string]interface{}{"GroupId": "00GB", "UserOrGroupId": "005A"}},
+		}, true)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, "0PaA", results[0].ID)
+
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, CompositeSObjectsPath, path)
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(body, &got))
+		require.Equal(t, true, got["allOrNone"])
+		records, ok := got["records"].([]any)
+		require.True(t, ok)
+		require.Len(t, records, 2)
+		first, ok := records[0].(map[string]any)
+		require.True(t, ok)
+		require.Equal(t, map[string]any{"type": TableNamePermissionAssignments}, first["attributes"])
+		require.Equal(t, "005A", first["AssigneeId"])
+	})
+
+	t.Run("should map duplicate records to ErrObjectAlreadyExists", func(t *testing.T) {
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, []map[string]any{
+				{"success": false, "errors": []map[string]any{{"statusCode": "ALL_OR_NONE_OPERATION_ROLLED_BACK", "message": "rolled back"}}},
+				{"success": false, "errors": []map[string]any{{"statusCode": "DUPLICATE_VALUE", "message": "duplicate value found"}}},
+			})
+		})
+
+		results, _, err := c.CreateObjects(ctx, []*SObjectRecord{
+			{Type: TableNameUserTerritory2Assoc, Fields: map[string]interface{}{"UserId": "005A", "Territory2Id": "0MIA"}},
+			{Type: TableNameUserTerritory2Assoc, Fields: map[string]interface{}{"UserId": "005B", "Territory2Id": "0MIA"}},
+		}, true)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrObjectAlreadyExists)
+		require.Len(t, results, 2)
+		require.ErrorIs(t, results[1].Err(), ErrObjectAlreadyExists)
+		// The rolled-back record is not the cause, so it stays out of the error.
+		require.NotContains(t, err.Error(), "rolled back")
+	})
+
+	t.Run("should refuse more records than one request can hold", func(t *testing.T) {
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		})
+		records := make([]*SObjectRecord, compositeSObjectsMaxRecords+1)
+		for i := range records {
+			records[i] = &SObjectRecord{Type: TableNameGroupMemberships}
+		}
+		_, _, err := c.CreateObjects(ctx, records, false)
+		require.Error(t, err)
+	})
+}
+
+func TestUpdateObjects(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		writeJSON(t, w, []map[string]any{{"id": "0R0A", "success": true, "errors": []any{}}})
+	})
+
+	_, _, err := c.UpdateObjects(ctx, []*SObjectRecord{{Type: TableNameUserTerritory2Assoc}}, true)
+	require.Error(t, err)
+
+	results, _, err := c.UpdateObjects(ctx, []*SObjectRecord{
+		{Type: TableNameUserTerritory2Assoc, ID: "0R0A", Fields: map[string]interface{}{"RoleInTerritory2": "Owner"}},
+	}, true)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
+
+func TestDeleteObjects(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "0PaA,0PaB", r.URL.Query().Get("ids"))
+		assert.Equal(t, "false", r.URL.Query().Get("allOrNone"))
+		writeJSON(t, w, []map[string]any{
+			{"id": "0PaA", "success": true, "errors": []any{}},
+			{"id": "0PaB", "success": false, "errors": []map[string]any{{"statusCode": "ENTITY_IS_DELETED", "message": "entity is deleted"}}},
+		})
+	})
+
+	results, _, err := c.DeleteObjects(ctx, []string{"0PaA", "0PaB"}, false)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrObjectNotFound))
+	require.Len(t, results, 2)
+	require.NoError(t, results[0].Err())
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// DefaultOwnershipTransferObjects are the objects whose records are handed to
+// a successor before a user is deactivated, unless the caller lists others.
+var DefaultOwnershipTransferObjects = []string{
+	"Account",
+	"Opportunity",
+	"Case",
+	"Lead",
+}
+
+// queryAllIDs returns the IDs of every record matching the query, following
+// pagination to the end.
+func (c *SalesforceClient) queryAllIDs(
+	ctx context.Context,
+	query *SalesforceQuery,
+) (
+	[]string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	ids := make([]string, 0)
+	paginationPath := ""
+	for {
+		records, nextPath, ratelimitData, err := c.query(ctx, query, paginationPath, PageSizeDefault)
+		if err != nil {
+			return nil, ratelimitData, err
+		}
+		for _, record := range records {
+			ids = append(ids, record.ID())
+		}
+		if nextPath == "" {
+			return ids, ratelimitData, nil
+		}
+		paginationPath = nextPath
+	}
+}
+
+// TransferOwnership reassigns every record of the given type owned by one
+// user to another, 200 records per sObject Collections request. Converted
+// leads can't be edited and would fail their whole request, so they're left
+// with the user. Returns how many records were transferred, which is less
+// than all of them on error.
+func (c *SalesforceClient) TransferOwnership(
+	ctx context.Context,
+	sobjectType string,
+	fromUserID string,
+	toUserID string,
+) (
+	int,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewIDQuery(sobjectType).WhereEq("OwnerId", fromUserID)
+	if sobjectType == TableNameLeads {
+		query.WhereBoolEq("IsConverted", false)
+	}
+	ids, ratelimitData, err := c.queryAllIDs(ctx, query)
+	if err != nil {
+		return 0, ratelimitData, err
+	}
+
+	transferred := 0
+	for chunk := range slices.Chunk(ids, compositeSObjectsMaxRecords) {
+		records := make([]*SObjectRecord, 0, len(chunk))
+		for _, id := range chunk {
+			records = append(records, &SObjectRecord{
+				Type:   sobjectType,
+				ID:     id,
+				Fields: map[string]interface{}{"OwnerId": toUserID},
+			})
+		}
+		_, ratelimitData, err = c.UpdateObjects(ctx, records, true)
+		if err != nil {
+			return transferred, ratelimitData, fmt.Errorf(
+				"baton-salesforce: failed to transfer %s records: %w",
+				sobjectType,
+				err,
+			)
+		}
+		transferred += len(chunk)
+	}
+
+	ctxzap.Extract(ctx).Debug(
+		"salesforce-client: transferred record ownership",
+		zap.String("sobject_type", sobjectType),
+		zap.String("from_user_id", fromUserID),
+		zap.String("to_user_id", toUserID),
+		zap.Int("count", transferred),
+	)
+	return transferred, ratelimitData, nil
+}
+
+// RemoveGroupMemberships removes the user from every public group and queue.
+// Returns how many memberships were removed.
+func (c *SalesforceClient) RemoveGroupMemberships(
+	ctx context.Context,
+	userID string,
+) (
+	int,
+	*v2.RateLimitDescription,
+	error,
+) {
+	return c.deleteAll(
+		ctx,
+		NewIDQuery(TableNameGroupMemberships).WhereEq("UserOrGroupId", userID),
+	)
+}
+
+// RemoveTerritoryMemberships removes the user from every territory. Orgs
+// without Enterprise Territory Management have no memberships to remove.
+func (c *SalesforceClient) RemoveTerritoryMemberships(
+	ctx context.Context,
+	userID string,
+) (
+	int,
+	*v2.RateLimitDescription,
+	error,
+) {
+	removed, ratelimitData, err := c.deleteAll(
+		ctx,
+		NewIDQuery(TableNameUserTerritory2Assoc).WhereEq("UserId", userID),
+	)
+	if err != nil && errors.Is(err, ErrSObjectNotSupported) {
+		return 0, ratelimitData, nil
+	}
+	return removed, ratelimitData, err
+}
+
+// deleteAll deletes every record matching the query, 200 per request.
+func (c *SalesforceClient) deleteAll(
+	ctx context.Context,
+	query *SalesforceQuery,
+) (
+	int,
+	*v2.RateLimitDescription,
+	error,
+) {
+	ids, ratelimitData, err := c.queryAllIDs(ctx, query)
+	if err != nil {
+		return 0, ratelimitData, err
+	}
+
+	deleted := 0
+	for chunk := range slices.Chunk(ids, compositeSObjectsMaxRecords) {
+		_, ratelimitData, err = c.DeleteObjects(ctx, chunk, true)
+		if err != nil {
+			return deleted, ratelimitData, err
+		}
+		deleted += len(chunk)
+	}
+	return deleted, ratelimitData, nil
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferOwnership(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should follow pagination and update 200 records at a time", func(t *testing.T) {
+		var soql string
+		var batches []int
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPatch:
+				assert.Equal(t, CompositeSObjectsPath, r.URL.Path)
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				var got struct {
+					AllOrNone bool             `json:"allOrNone"`
+					Records   []map[string]any `json:"records"`
+				}
+				assert.NoError(t, json.Unmarshal(body, &got))
+				assert.True(t, got.AllOrNone)
+				results := make([]map[string]any, 0, len(got.Records))
+				for _, record := range got.Records {
+					assert.Equal(t, "005NEW", record["OwnerId"])
+					assert.Equal(t, map[string]any{"type": "Case"}, record["attributes"])
+					results = append(results, map[string]any{"id": record["Id"], "success": true})
+				}
+				batches = append(batches, len(got.Records))
+				writeJSON(t, w, results)
+			case strings.Contains(r.URL.Path, "01gNEXT"):
+				writeJSON(t, w, map[string]any{
+					"totalSize": 250,
+					"done":      true,
+					"records":   caseRecords(200, 250),
+				})
+			default:
+				soql = r.URL.Query().Get("q")
+				writeJSON(t, w, map[string]any{
+					"totalSize":      250,
+					"done":           false,
+					"nextRecordsUrl": "/services/data/v64.0/query/01gNEXT-200",
+					"records":        caseRecords(0, 200),
+				})
+			}
+		})
+
+		transferred, _, err := c.TransferOwnership(ctx, "Case", "005OLD", "005NEW")
+		require.NoError(t, err)
+		require.Contains(t, soql, "FROM Case WHERE OwnerId = '005OLD'")
+		require.Equal(t, 250, transferred)
+		require.Equal(t, []int{200, 50}, batches)
+	})
+
+	t.Run("should report what was transferred before a failure", func(t *testing.T) {
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPatch {
+				writeJSON(t, w, []map[string]any{{
+					"success": false,
+					"errors": []map[string]any{
+						{"statusCode": "UNABLE_TO_LOCK_ROW", "message": "unable to obtain exclusive access"},
+					},
+				}})
+				return
+			}
+			writeJSON(t, w, map[string]any{
+				"totalSize": 1,
+				"done":      true,
+				"records":   caseRecords(0, 1),
+			})
+		})
+
+		transferred, _, err := c.TransferOwnership(ctx, "Case", "005OLD", "005NEW")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "UNABLE_TO_LOCK_ROW")
+		require.Equal(t, 0, transferred)
+	})
+	t.Run("should leave converted leads with the user", func(t *testing.T) {
+		var soql string
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			soql = r.URL.Query().Get("q")
+			writeJSON(t, w, map[string]any{"totalSize": 0, "done": true, "records": []any{}})
+		})
+
+		transferred, _, err := c.TransferOwnership(ctx, "Lead", "005OLD", "005NEW")
+		require.NoError(t, err)
+		require.Contains(t, soql, "FROM Lead WHERE OwnerId = '005OLD' AND IsConverted = false")
+		require.Equal(t, 0, transferred)
+	})
+}
+
+func TestRemoveTerritoryMemberships(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should skip orgs without territory management", func(t *testing.T) {
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`[{"message":"sObject type 'UserTerritory2Association' is not supported.","errorCode":"INVALID_TYPE"}]`))
+		})
+
+		removed, _, err := c.RemoveTerritoryMemberships(ctx, "005OLD")
+		require.NoError(t, err)
+		require.Equal(t, 0, removed)
+	})
+}
+
+func caseRecords(from int, to int) []map[string]any {
+	records := make([]map[string]any, 0, to-from)
+	for i := from; i < to; i++ {
+		records = append(records, map[string]any{"Id": fmt.Sprintf("500%05d", i)})
+	}
+	return records
+}
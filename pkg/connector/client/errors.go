@@ -0,0 +1,149 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/conductorone/simpleforce"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrObjectNotFound = errors.New("salesforce object does not exist")
+var ErrObjectAlreadyExists = errors.New("salesforce object already exists")
+var ErrRoleAlreadyCleared = errors.New("salesforce territory role is already empty")
+var ErrRoleMismatch = errors.New("salesforce territory role does not match expected role")
+
+var ErrInsufficientAccess = errors.New("salesforce user lacks access to perform this operation")
+var ErrInvalidSession = errors.New("salesforce session is invalid or expired")
+var ErrRequestLimitExceeded = errors.New("salesforce API request limit exceeded")
+var ErrRowLocked = errors.New("salesforce record is locked by another operation")
+var ErrServerUnavailable = errors.New("salesforce is temporarily unavailable")
+var ErrInvalidRequest = errors.New("salesforce rejected the request as invalid")
+var ErrChangeRejected = errors.New("salesforce rejected the change")
+var ErrSObjectNotSupported = errors.New("salesforce object type is not supported by this org")
+
+// errorClass is what a Salesforce error code means to the connector: the gRPC
+// code ConductorOne uses to decide whether to retry, and the sentinel callers
+// match with errors.Is.
+type errorClass struct {
+	code     codes.Code
+	sentinel error
+}
+
+// errorClasses maps the errorCode of a Salesforce error body to its class.
+// Codes starting with INSUFFICIENT_ACCESS are matched by prefix in
+// classifyErrorCode since Salesforce has several variants of them.
+// https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_calls_concepts_core_data_objects.htm#statuscode
+var errorClasses = map[string]errorClass{
+	"INVALID_SESSION_ID":     {codes.Unauthenticated, ErrInvalidSession},
+	"API_DISABLED_FOR_ORG":   {codes.PermissionDenied, ErrInsufficientAccess},
+	"REQUEST_LIMIT_EXCEEDED": {codes.ResourceExhausted, ErrRequestLimitExceeded},
+	"UNABLE_TO_LOCK_ROW":     {codes.Unavailable, ErrRowLocked},
+	"SERVER_UNAVAILABLE":     {codes.Unavailable, ErrServerUnavailable},
+
+	"FIELD_INTEGRITY_EXCEPTION":       {codes.InvalidArgument, ErrInvalidRequest},
+	"REQUIRED_FIELD_MISSING":          {codes.InvalidArgument, ErrInvalidRequest},
+	"INVALID_FIELD":                   {codes.InvalidArgument, ErrInvalidRequest},
+	"INVALID_FIELD_FOR_INSERT_UPDATE": {codes.InvalidArgument, ErrInvalidRequest},
+	"INVALID_CROSS_REFERENCE_KEY":     {codes.InvalidArgument, ErrInvalidRequest},
+	"MALFORMED_ID":                    {codes.InvalidArgument, ErrInvalidRequest},
+	"MALFORMED_QUERY":                 {codes.InvalidArgument, ErrInvalidRequest},
+	"STRING_TOO_LONG":                 {codes.InvalidArgument, ErrInvalidRequest},
+
+	"CANNOT_INSERT_UPDATE_ACTIVATE_ENTITY": {codes.FailedPrecondition, ErrChangeRejected},
+	"FIELD_CUSTOM_VALIDATION_EXCEPTION":    {codes.FailedPrecondition, ErrChangeRejected},
+	"INVALID_TYPE":                         {codes.FailedPrecondition, ErrSObjectNotSupported},
+
+	"DUPLICATE_VALUE":   {codes.AlreadyExists, ErrObjectAlreadyExists},
+	"ENTITY_IS_DELETED": {codes.NotFound, ErrObjectNotFound},
+	"NOT_FOUND":         {codes.NotFound, ErrObjectNotFound},
+}
+
+// Error is a Salesforce API failure classified by its error code. It matches
+// both its sentinel and the underlying simpleforce.SalesforceError, and
+// carries a gRPC status so baton-sdk reports permanent and retryable failures
+// differently.
+type Error struct {
+	Code      codes.Code
+	ErrorCode string
+	sentinel  error
+	err       error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.sentinel, e.err)
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.sentinel, e.err}
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	return status.New(e.Code, e.Error())
+}
+
+// classifyErrorCode looks up a Salesforce errorCode, falling back to the HTTP
+// status for responses without a recognized code.
+func classifyErrorCode(errorCode string, httpCode int) (errorClass, bool) {
+	if class, ok := errorClasses[errorCode]; ok {
+		return class, true
+	}
+	if strings.HasPrefix(errorCode, "INSUFFICIENT_ACCESS") {
+		return errorClass{codes.PermissionDenied, ErrInsufficientAccess}, true
+	}
+
+	switch {
+	case httpCode == http.StatusUnauthorized:
+		return errorClass{codes.Unauthenticated, ErrInvalidSession}, true
+	case httpCode == http.StatusForbidden:
+		return errorClass{codes.PermissionDenied, ErrInsufficientAccess}, true
+	case httpCode == http.StatusNotFound:
+		return errorClass{codes.NotFound, ErrObjectNotFound}, true
+	case httpCode == http.StatusTooManyRequests:
+		return errorClass{codes.ResourceExhausted, ErrRequestLimitExceeded}, true
+	case httpCode >= http.StatusInternalServerError:
+		return errorClass{codes.Unavailable, ErrServerUnavailable}, true
+	}
+	return errorClass{}, false
+}
+
+// classifyError wraps an error carrying a simpleforce.SalesforceError in an
+// *Error. Errors that are already classified, or that Salesforce didn't
+// produce, are returned unchanged.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	var sfErr simpleforce.SalesforceError
+	if !errors.As(err, &sfErr) {
+		return err
+	}
+	class, ok := classifyErrorCode(sfErr.ErrorCode, sfErr.HttpCode)
+	if !ok {
+		return err
+	}
+	return &Error{
+		Code:      class.code,
+		ErrorCode: sfErr.ErrorCode,
+		sentinel:  class.sentinel,
+		err:       err,
+	}
+}
+
+// salesforceErrorFromBody guards simpleforce.ParseSalesforceError, which
+// indexes the first element of a JSON error array without checking its length.
+func salesforceErrorFromBody(statusCode int, body []byte) error {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("[]")) {
+		return nil
+	}
+	return simpleforce.ParseSalesforceError(statusCode, trimmed)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/conductorone/simpleforce"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		errorCode string
+		httpCode  int
+		code      codes.Code
+		sentinel  error
+	}{
+		{"INSUFFICIENT_ACCESS_ON_CROSS_REFERENCE_ENTITY", http.StatusBadRequest, codes.PermissionDenied, ErrInsufficientAccess},
+		{"INSUFFICIENT_ACCESS_OR_READONLY", http.StatusBadRequest, codes.PermissionDenied, ErrInsufficientAccess},
+		{"INVALID_SESSION_ID", http.StatusUnauthorized, codes.Unauthenticated, ErrInvalidSession},
+		{"REQUEST_LIMIT_EXCEEDED", http.StatusForbidden, codes.ResourceExhausted, ErrRequestLimitExceeded},
+		{"UNABLE_TO_LOCK_ROW", http.StatusBadRequest, codes.Unavailable, ErrRowLocked},
+		{"FIELD_INTEGRITY_EXCEPTION", http.StatusBadRequest, codes.InvalidArgument, ErrInvalidRequest},
+		{"CANNOT_INSERT_UPDATE_ACTIVATE_ENTITY", http.StatusBadRequest, codes.FailedPrecondition, ErrChangeRejected},
+		{"INVALID_TYPE", http.StatusBadRequest, codes.FailedPrecondition, ErrSObjectNotSupported},
+		{"DUPLICATE_VALUE", http.StatusBadRequest, codes.AlreadyExists, ErrObjectAlreadyExists},
+		{"ENTITY_IS_DELETED", http.StatusBadRequest, codes.NotFound, ErrObjectNotFound},
+		{"", http.StatusServiceUnavailable, codes.Unavailable, ErrServerUnavailable},
+		{"SOMETHING_NEW", http.StatusNotFound, codes.NotFound, ErrObjectNotFound},
+	}
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("%s/%d", testCase.errorCode, testCase.httpCode), func(t *testing.T) {
+			sfErr := simpleforce.SalesforceError{
+				Message:   "salesforce error",
+				HttpCode:  testCase.httpCode,
+				ErrorCode: testCase.errorCode,
+			}
+			err := classifyError(fmt.Errorf("wrapped: %w", sfErr))
+
+			require.ErrorIs(t, err, testCase.sentinel)
+			require.Equal(t, testCase.code, status.Code(err))
+			var unwrapped simpleforce.SalesforceError
+			require.ErrorAs(t, err, &unwrapped)
+			require.Equal(t, testCase.errorCode, unwrapped.ErrorCode)
+		})
+	}
+
+	t.Run("should leave unknown errors alone", func(t *testing.T) {
+		sfErr := simpleforce.SalesforceError{HttpCode: http.StatusBadRequest, ErrorCode: "SOMETHING_NEW"}
+		require.Equal(t, sfErr, classifyError(sfErr))
+
+		plain := errors.New("connection reset")
+		require.Equal(t, plain, classifyError(plain))
+		require.Nil(t, classifyError(nil))
+	})
+}
+
+func TestRestRequestClassifiesErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`[{"message":"unable to obtain exclusive access to this record","errorCode":"UNABLE_TO_LOCK_ROW"}]`))
+	})
+
+	_, _, err := c.DeleteObjects(ctx, []string{"0PaA"}, true)
+	require.ErrorIs(t, err, ErrRowLocked)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,432 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/simpleforce"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// GetPermissionSetLicenses - SELECT Id, MasterLabel, DeveloperName,
+// TotalLicenses, UsedLicenses, Status, ExpirationDate FROM PermissionSetLicense.
+func (c *SalesforceClient) GetPermissionSetLicenses(
+	ctx context.Context,
+	pageToken string,
+	pageSize int,
+) (
+	[]*PermissionSetLicense,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNamePermissionSetLicenses)
+	records, paginationUrl, ratelimitData, err := c.query(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+
+	licenses := make([]*PermissionSetLicense, 0, len(records))
+	for _, record := range records {
+		total, err := getIntField(record, "TotalLicenses")
+		if err != nil {
+			return nil, "", ratelimitData, err
+		}
+		used, err := getIntField(record, "UsedLicenses")
+		if err != nil {
+			return nil, "", ratelimitData, err
+		}
+		licenses = append(licenses, &PermissionSetLicense{
+			ID:             record.ID(),
+			Name:           record.StringField("MasterLabel"),
+			DeveloperName:  record.StringField("DeveloperName"),
+			TotalLicenses:  total,
+			UsedLicenses:   used,
+			Status:         record.StringField("Status"),
+			ExpirationDate: record.StringField("ExpirationDate"),
+		})
+	}
+	return licenses, paginationUrl, ratelimitData, nil
+}
+
+// GetPermissionSetLicenseAssignments - SELECT Id, AssigneeId FROM
+// PermissionSetLicenseAssign WHERE PermissionSetLicenseId = licenseID.
+func (c *SalesforceClient) GetPermissionSetLicenseAssignments(
+	ctx context.Context,
+	licenseID string,
+	pageToken string,
+	pageSize int,
+) (
+	[]*PermissionSetLicenseAssignment,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNamePermissionSetLicenseAssignments).WhereEq("PermissionSetLicenseId", licenseID)
+	records, paginationUrl, ratelimitData, err := c.queryLarge(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+
+	assignments := make([]*PermissionSetLicenseAssignment, 0, len(records))
+	for _, record := range records {
+		assignments = append(assignments, &PermissionSetLicenseAssignment{
+			ID:                     record.ID(),
+			UserID:                 record.StringField("AssigneeId"),
+			PermissionSetLicenseID: record.StringField("PermissionSetLicenseId"),
+		})
+	}
+	return assignments, paginationUrl, ratelimitData, nil
+}
+
+func (c *SalesforceClient) getPermissionSetLicenseAssignment(
+	ctx context.Context,
+	userID string,
+	licenseID string,
+) (
+	*simpleforce.SObject,
+	*v2.RateLimitDescription,
+	error,
+) {
+	return c.getSObject(
+		ctx,
+		NewQuery(TableNamePermissionSetLicenseAssignments).
+			WhereEq("AssigneeId", userID).
+			WhereEq("PermissionSetLicenseId", licenseID),
+	)
+}
+
+func (c *SalesforceClient) AddUserToPermissionSetLicense(
+	ctx context.Context,
+	userID string,
+	licenseID string,
+) (*v2.RateLimitDescription, error) {
+	return c.CreateObject(
+		ctx,
+		TableNamePermissionSetLicenseAssignments,
+		map[string]interface{}{
+			"AssigneeId":             userID,
+			"PermissionSetLicenseId": licenseID,
+		},
+	)
+}
+
+func (c *SalesforceClient) RemoveUserFromPermissionSetLicense(
+	ctx context.Context,
+	userID string,
+	licenseID string,
+) (*v2.RateLimitDescription, error) {
+	found, ratelimitData, err := c.getPermissionSetLicenseAssignment(ctx, userID, licenseID)
+	if err != nil {
+		return ratelimitData, err
+	}
+	return c.DeleteObject(ctx, TableNamePermissionSetLicenseAssignments, found.ID())
+}
+
+// FeatureLicenses are the feature licenses assigned through User checkboxes.
+// The API doesn't report their seat counts, and a checkbox only exists when
+// the org has the feature.
+var FeatureLicenses = []FeatureLicense{
+	{Field: "UserPermissionsMarketingUser", Name: "Marketing User"},
+	{Field: "UserPermissionsKnowledgeUser", Name: "Knowledge User"},
+	{Field: "UserPermissionsInteractionUser", Name: "Flow User"},
+	{Field: "UserPermissionsSupportUser", Name: "Service Cloud User"},
+	{Field: "UserPermissionsSFContentUser", Name: "Salesforce CRM Content User"},
+	{Field: "UserPermissionsOfflineUser", Name: "Offline User"},
+	{Field: "UserPermissionsMobileUser", Name: "Apex Mobile User"},
+	{Field: "UserPermissionsLiveAgentUser", Name: "Chat User"},
+}
+
+// checkFeatureLicenseField makes sure field is one of FeatureLicenses, as it
+// ends up in SOQL and in the User update.
+func checkFeatureLicenseField(field string) error {
+	for _, license := range FeatureLicenses {
+		if license.Field == field {
+			return nil
+		}
+	}
+	return fmt.Errorf("baton-salesforce: unknown feature license %q", field)
+}
+
+// GetFeatureLicenseHolders lists the active users with the feature license -
+// SELECT Id FROM User WHERE field = true AND IsActive = true. If the org
+// doesn't have the feature, the field doesn't exist and the query fails with
+// INVALID_FIELD, so no users are returned. Any other error is passed through.
+func (c *SalesforceClient) GetFeatureLicenseHolders(
+	ctx context.Context,
+	field string,
+	pageToken string,
+	pageSize int,
+) (
+	[]*SalesforceUser,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	if err := checkFeatureLicenseField(field); err != nil {
+		return nil, "", nil, err
+	}
+	query := NewIDQuery(TableNameUsers).
+		WhereBoolEq(field, true).
+		WhereBoolEq("IsActive", true)
+	records, paginationUrl, ratelimitData, err := c.queryLarge(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		var sfErr *Error
+		if errors.As(err, &sfErr) && sfErr.ErrorCode == "INVALID_FIELD" {
+			ctxzap.Extract(ctx).Info(
+				"salesforce-client: feature license field not available; the feature is not enabled in the org",
+				zap.String("field", field),
+				zap.Error(err),
+			)
+			return []*SalesforceUser{}, "", ratelimitData, nil
+		}
+		return nil, "", ratelimitData, err
+	}
+
+	users := make([]*SalesforceUser, 0, len(records))
+	for _, record := range records {
+		users = append(users, &SalesforceUser{ID: record.ID()})
+	}
+	return users, paginationUrl, ratelimitData, nil
+}
+
+// SetUserFeatureLicense turns the user's feature license checkbox on or off.
+func (c *SalesforceClient) SetUserFeatureLicense(
+	ctx context.Context,
+	userID string,
+	field string,
+	enabled bool,
+) (*v2.RateLimitDescription, error) {
+	if err := checkFeatureLicenseField(field); err != nil {
+		return nil, err
+	}
+	return c.UpdateObject(ctx, TableNameUsers, userID, map[string]interface{}{field: enabled})
+}
+
+// GetUserLicenses - SELECT Id, Name, LicenseDefinitionKey, TotalLicenses,
+// UsedLicenses, Status FROM UserLicense.
+func (c *SalesforceClient) GetUserLicenses(
+	ctx context.Context,
+	pageToken string,
+	pageSize int,
+) (
+	[]*SalesforceUserLicense,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNameUserLicenses)
+	records, paginationUrl, ratelimitData, err := c.query(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+
+	licenses := make([]*SalesforceUserLicense, 0, len(records))
+	for _, record := range records {
+		total, err := getIntField(record, "TotalLicenses")
+		if err != nil {
+			return nil, "", ratelimitData, err
+		}
+		used, err := getIntField(record, "UsedLicenses")
+		if err != nil {
+			return nil, "", ratelimitData, err
+		}
+		licenses = append(licenses, &SalesforceUserLicense{
+			ID:                   record.ID(),
+			Name:                 record.StringField("Name"),
+			LicenseDefinitionKey: record.StringField("LicenseDefinitionKey"),
+			TotalLicenses:        total,
+			UsedLicenses:         used,
+			Status:               record.StringField("Status"),
+		})
+	}
+	return licenses, paginationUrl, ratelimitData, nil
+}
+
+// GetUserLicenseHolders lists the active users whose profile consumes the
+// user license - SELECT Id, ProfileId FROM User WHERE ProfileId IN (SELECT Id
+// FROM Profile WHERE UserLicenseId = licenseID) AND IsActive = true.
+// Deactivated users don't use a seat, so they are left out.
+func (c *SalesforceClient) GetUserLicenseHolders(
+	ctx context.Context,
+	licenseID string,
+	pageToken string,
+	pageSize int,
+) (
+	[]*SalesforceUser,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNameUsers, "ProfileId").
+		WhereInSubQuery(
+			"ProfileId",
+			NewIDQuery(TableNameProfiles).WhereEq("UserLicenseId", licenseID),
+		).
+		WhereBoolEq("IsActive", true)
+	records, paginationUrl, ratelimitData, err := c.queryLarge(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+
+	users := make([]*SalesforceUser, 0, len(records))
+	for _, record := range records {
+		users = append(users, &SalesforceUser{
+			ID:        record.ID(),
+			ProfileID: record.StringField("ProfileId"),
+		})
+	}
+	return users, paginationUrl, ratelimitData, nil
+}
+
+// GetPackageLicenses - SELECT Id, NamespacePrefix, AllowedLicenses,
+// UsedLicenses, Status, ExpirationDate FROM PackageLicense.
+func (c *SalesforceClient) GetPackageLicenses(
+	ctx context.Context,
+	pageToken string,
+	pageSize int,
+) (
+	[]*PackageLicense,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNamePackageLicenses)
+	records, paginationUrl, ratelimitData, err := c.query(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+
+	licenses := make([]*PackageLicense, 0, len(records))
+	for _, record := range records {
+		allowed, err := getIntField(record, "AllowedLicenses")
+		if err != nil {
+			return nil, "", ratelimitData, err
+		}
+		used, err := getIntField(record, "UsedLicenses")
+		if err != nil {
+			return nil, "", ratelimitData, err
+		}
+		licenses = append(licenses, &PackageLicense{
+			ID:              record.ID(),
+			NamespacePrefix: record.StringField("NamespacePrefix"),
+			AllowedLicenses: allowed,
+			UsedLicenses:    used,
+			Status:          record.StringField("Status"),
+			ExpirationDate:  record.StringField("ExpirationDate"),
+		})
+	}
+	return licenses, paginationUrl, ratelimitData, nil
+}
+
+// GetUserPackageLicenses - SELECT Id, UserId, PackageLicenseId FROM
+// UserPackageLicense WHERE PackageLicenseId = licenseID.
+func (c *SalesforceClient) GetUserPackageLicenses(
+	ctx context.Context,
+	licenseID string,
+	pageToken string,
+	pageSize int,
+) (
+	[]*UserPackageLicense,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNameUserPackageLicenses).WhereEq("PackageLicenseId", licenseID)
+	records, paginationUrl, ratelimitData, err := c.queryLarge(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+
+	assignments := make([]*UserPackageLicense, 0, len(records))
+	for _, record := range records {
+		assignments = append(assignments, &UserPackageLicense{
+			ID:               record.ID(),
+			UserID:           record.StringField("UserId"),
+			PackageLicenseID: record.StringField("PackageLicenseId"),
+		})
+	}
+	return assignments, paginationUrl, ratelimitData, nil
+}
+
+func (c *SalesforceClient) getUserPackageLicense(
+	ctx context.Context,
+	userID string,
+	licenseID string,
+) (
+	*simpleforce.SObject,
+	*v2.RateLimitDescription,
+	error,
+) {
+	return c.getSObject(
+		ctx,
+		NewQuery(TableNameUserPackageLicenses).
+			WhereEq("UserId", userID).
+			WhereEq("PackageLicenseId", licenseID),
+	)
+}
+
+func (c *SalesforceClient) AddUserToPackageLicense(
+	ctx context.Context,
+	userID string,
+	licenseID string,
+) (*v2.RateLimitDescription, error) {
+	return c.CreateObject(
+		ctx,
+		TableNameUserPackageLicenses,
+		map[string]interface{}{
+			"UserId":           userID,
+			"PackageLicenseId": licenseID,
+		},
+	)
+}
+
+func (c *SalesforceClient) RemoveUserFromPackageLicense(
+	ctx context.Context,
+	userID string,
+	licenseID string,
+) (*v2.RateLimitDescription, error) {
+	found, ratelimitData, err := c.getUserPackageLicense(ctx, userID, licenseID)
+	if err != nil {
+		return ratelimitData, err
+	}
+	return c.DeleteObject(ctx, TableNameUserPackageLicenses, found.ID())
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFeatureLicenseHolders(t *testing.T) {
+	ctx := context.Background()
+
+	queryError := func(errorCode string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`[{"message":"query failed","errorCode":"` + errorCode + `"}]`))
+		}
+	}
+
+	t.Run("should return no users when the org doesn't have the field", func(t *testing.T) {
+		c := newTestClient(ctx, t, queryError("INVALID_FIELD"))
+
+		users, nextToken, _, err := c.GetFeatureLicenseHolders(ctx, "UserPermissionsMarketingUser", "", 100)
+		require.NoError(t, err)
+		require.Empty(t, users)
+		require.Empty(t, nextToken)
+	})
+
+	t.Run("should pass other invalid requests through", func(t *testing.T) {
+		c := newTestClient(ctx, t, queryError("MALFORMED_QUERY"))
+
+		_, _, _, err := c.GetFeatureLicenseHolders(ctx, "UserPermissionsMarketingUser", "", 100)
+		require.ErrorIs(t, err, ErrInvalidRequest)
+	})
+}
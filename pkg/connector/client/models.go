@@ -10,6 +10,8 @@ type SalesforceUser struct {
 	LastName             string     `json:"last_name"`
 	UserType             string     `json:"user_type"`
 	LicenseDefinitionKey string     `json:"license_definition_key"`
+	ProfileID            string     `json:"profile_id"`
+	UserRoleID           string     `json:"user_role_id"`
 	IsActive             bool       `json:"is_active"`
 	LastLoginDate        *time.Time `json:"last_login_date"`
 }
@@ -19,6 +21,8 @@ type SalesforceGroupMembership struct {
 	GroupID     string
 	PrincipalID string // could be user or group
 	IsGroup     bool
+	// ModifiedAt is the record's SystemModstamp. Only change queries set it.
+	ModifiedAt time.Time
 }
 
 type SalesforceGroup struct {
@@ -30,8 +34,9 @@ type SalesforceGroup struct {
 }
 
 type SalesforceRole struct {
-	ID   string
-	Name string
+	ID           string
+	Name         string
+	ParentRoleID string
 }
 
 type SalesforcePermission struct {
@@ -40,6 +45,9 @@ type SalesforcePermission struct {
 	Label     string
 	Type      string
 	ProfileID string
+	// SystemPermissions lists which of the requested system permissions
+	// (ModifyAllData, ViewAllData, ...) the permission set turns on.
+	SystemPermissions []string
 }
 
 type SalesforceProfile struct {
@@ -49,8 +57,12 @@ type SalesforceProfile struct {
 }
 
 type SalesforceUserLicense struct {
-	ID   string
-	Name string
+	ID                   string
+	Name                 string
+	LicenseDefinitionKey string
+	TotalLicenses        int64
+	UsedLicenses         int64
+	Status               string
 }
 
 type PermissionSetAssignment struct {
@@ -59,6 +71,83 @@ type PermissionSetAssignment struct {
 	PermissionSetID      string
 	PermissionSetGroupID string
 	IsActive             bool
+	// ModifiedAt is the record's SystemModstamp. Only change queries set it.
+	ModifiedAt time.Time
+}
+
+// ObjectPermission is the access a permission set, or the permission set
+// owned by a profile, gives to one object type.
+type ObjectPermission struct {
+	ID               string
+	PermissionSetID  string
+	SobjectType      string
+	Read             bool
+	Create           bool
+	Edit             bool
+	Delete           bool
+	ViewAllRecords   bool
+	ModifyAllRecords bool
+}
+
+// FieldPermission is the field-level security a permission set, or the
+// permission set owned by a profile, gives on one field.
+type FieldPermission struct {
+	ID              string
+	PermissionSetID string
+	SobjectType     string
+	// Field is the qualified field name, e.g. Contact.SSN__c.
+	Field string
+	Read  bool
+	Edit  bool
+}
+
+// PermissionSetLicense is a license for a paid add-on feature, assigned to
+// users on top of their user license.
+type PermissionSetLicense struct {
+	ID             string
+	Name           string
+	DeveloperName  string
+	TotalLicenses  int64
+	UsedLicenses   int64
+	Status         string
+	ExpirationDate string
+}
+
+// FeatureLicense is a license for a feature such as Marketing User, assigned
+// by turning on a checkbox on the user rather than by an assignment record.
+type FeatureLicense struct {
+	// Field is the User checkbox, e.g. UserPermissionsMarketingUser.
+	Field string
+	Name  string
+}
+
+type PermissionSetLicenseAssignment struct {
+	ID                     string
+	UserID                 string
+	PermissionSetLicenseID string
+}
+
+// PackageLicense is the seat license of an installed managed package. A
+// negative AllowedLicenses means the package is licensed for the whole org.
+type PackageLicense struct {
+	ID              string
+	NamespacePrefix string
+	AllowedLicenses int64
+	UsedLicenses    int64
+	Status          string
+	ExpirationDate  string
+}
+
+// IsSiteLicense reports whether every user in the org can use the package
+// without being assigned a seat.
+func (l *PackageLicense) IsSiteLicense() bool {
+	return l.AllowedLicenses < 0
+}
+
+type UserPackageLicense struct {
+	ID               string
+	UserID           string
+	PackageLicenseID string
 }
 
 type PermissionSetGroup struct {
@@ -87,6 +176,26 @@ type ConnectedApplication struct {
 	LastModifiedDate string
 }
 
+// OauthToken is a user's authorization of a connected app. OauthToken only
+// refers to the app by name.
+type OauthToken struct {
+	ID           string
+	AppName      string
+	UserID       string
+	LastUsedDate string
+	UseCount     int64
+}
+
+// SetupEntityAccess gives a permission set access to a setup entity, such as
+// a connected app. ProfileID is set when the permission set is owned by a
+// profile.
+type SetupEntityAccess struct {
+	ID              string
+	PermissionSetID string
+	SetupEntityID   string
+	ProfileID       string
+}
+
 type BotDefinition struct {
 	ID            string
 	DeveloperName string
@@ -107,3 +216,26 @@ type UserTerritory2Association struct {
 	Territory2Id     string
 	RoleInTerritory2 string
 }
+
+type SetupAuditTrailEntry struct {
+	ID           string
+	Action       string
+	Section      string
+	Display      string
+	CreatedByID  string
+	DelegateUser string
+	CreatedDate  time.Time
+}
+
+type LoginHistoryEntry struct {
+	ID          string
+	UserID      string
+	LoginTime   time.Time
+	LoginType   string
+	LoginURL    string
+	SourceIP    string
+	Status      string
+	Application string
+	Browser     string
+	Platform    string
+}
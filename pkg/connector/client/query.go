@@ -2,30 +2,50 @@ package client
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/huandu/go-sqlbuilder"
 )
 
 const (
-	SalesforcePK                     = "Id"
-	allFieldsKeyword                 = "Fields(standard)"
-	TableNameGroupMemberships        = "GroupMember"
-	TableNameGroups                  = "Group"
-	TableNamePermissionAssignments   = "PermissionSetAssignment"
-	TableNamePermissionsSets         = "PermissionSet"
-	TableNameProfiles                = "Profile"
-	TableNameUserLicenses            = "UserLicense"
-	TableNameRoles                   = "UserRole"
-	TableNameUsers                   = "User"
-	TablePermissionSetGroup          = "PermissionSetGroup"
-	TablePermissionSetGroupComponent = "PermissionSetGroupComponent"
-	TableNameConnectedApps           = "ConnectedApplication"
-	TableNameUserLogin               = "UserLogin"
-	TableNameTerritory2              = "Territory2"
-	TableNameTerritory2Model         = "Territory2Model"
-	TableNameUserTerritory2Assoc     = "UserTerritory2Association"
-	TableNamePicklistValueInfo       = "PicklistValueInfo"
-	TableNameBotDefinition           = "BotDefinition"
+	SalesforcePK                             = "Id"
+	allFieldsKeyword                         = "Fields(standard)"
+	TableNameGroupMemberships                = "GroupMember"
+	TableNameGroups                          = "Group"
+	TableNamePermissionAssignments           = "PermissionSetAssignment"
+	TableNamePermissionsSets                 = "PermissionSet"
+	TableNameProfiles                        = "Profile"
+	TableNameUserLicenses                    = "UserLicense"
+	TableNameRoles                           = "UserRole"
+	TableNameUsers                           = "User"
+	TablePermissionSetGroup                  = "PermissionSetGroup"
+	TablePermissionSetGroupComponent         = "PermissionSetGroupComponent"
+	TableNameConnectedApps                   = "ConnectedApplication"
+	TableNameUserLogin                       = "UserLogin"
+	TableNameTerritory2                      = "Territory2"
+	TableNameTerritory2Model                 = "Territory2Model"
+	TableNameUserTerritory2Assoc             = "UserTerritory2Association"
+	TableNamePicklistValueInfo               = "PicklistValueInfo"
+	TableNameBotDefinition                   = "BotDefinition"
+	TableNameSetupAuditTrail                 = "SetupAuditTrail"
+	TableNameLoginHistory                    = "LoginHistory"
+	TableNameQueueSobjects                   = "QueueSobject"
+	TableNameObjectPermissions               = "ObjectPermissions"
+	TableNameFieldPermissions                = "FieldPermissions"
+	TableNamePermissionSetLicenses           = "PermissionSetLicense"
+	TableNamePermissionSetLicenseAssignments = "PermissionSetLicenseAssign"
+	TableNamePackageLicenses                 = "PackageLicense"
+	TableNameUserPackageLicenses             = "UserPackageLicense"
+	TableNameOauthTokens                     = "OauthToken"
+	TableNameSetupEntityAccess               = "SetupEntityAccess"
+	TableNameLeads                           = "Lead"
+
+	// systemPermissionFieldPrefix starts the name of every system permission
+	// column on PermissionSet and Profile, e.g. PermissionsModifyAllData.
+	systemPermissionFieldPrefix = "Permissions"
+
+	// soqlDatetimeLayout is the format SOQL accepts for unquoted datetime literals.
+	soqlDatetimeLayout = "2006-01-02T15:04:05Z"
 )
 
 var TableNamesToFieldsMapping = map[string][]string{
@@ -44,6 +64,7 @@ var TableNamesToFieldsMapping = map[string][]string{
 	},
 	TableNameRoles: {
 		"Name",
+		"ParentRoleId",
 	},
 	TableNameProfiles: {
 		"Name",
@@ -51,6 +72,10 @@ var TableNamesToFieldsMapping = map[string][]string{
 	},
 	TableNameUserLicenses: {
 		"Name",
+		"LicenseDefinitionKey",
+		"TotalLicenses",
+		"UsedLicenses",
+		"Status",
 	},
 	TableNamePermissionAssignments: {
 		"PermissionSetId",
@@ -127,6 +152,80 @@ var TableNamesToFieldsMapping = map[string][]string{
 		"MasterLabel",
 		"BotUserId",
 	},
+	TableNameSetupAuditTrail: {
+		"Action",
+		"Section",
+		"Display",
+		"CreatedDate",
+		"CreatedById",
+		"DelegateUser",
+	},
+	TableNameLoginHistory: {
+		"UserId",
+		"LoginTime",
+		"LoginType",
+		"LoginUrl",
+		"SourceIp",
+		"Status",
+		"Application",
+		"Browser",
+		"Platform",
+	},
+	TableNameQueueSobjects: {
+		"QueueId",
+		"SobjectType",
+	},
+	TableNameObjectPermissions: {
+		"ParentId",
+		"SobjectType",
+		"PermissionsRead",
+		"PermissionsCreate",
+		"PermissionsEdit",
+		"PermissionsDelete",
+		"PermissionsViewAllRecords",
+		"PermissionsModifyAllRecords",
+	},
+	TableNameFieldPermissions: {
+		"ParentId",
+		"SobjectType",
+		"Field",
+		"PermissionsRead",
+		"PermissionsEdit",
+	},
+	TableNamePermissionSetLicenses: {
+		"MasterLabel",
+		"DeveloperName",
+		"TotalLicenses",
+		"UsedLicenses",
+		"Status",
+		"ExpirationDate",
+	},
+	TableNamePermissionSetLicenseAssignments: {
+		"AssigneeId",
+		"PermissionSetLicenseId",
+	},
+	TableNamePackageLicenses: {
+		"NamespacePrefix",
+		"AllowedLicenses",
+		"UsedLicenses",
+		"Status",
+		"ExpirationDate",
+	},
+	TableNameUserPackageLicenses: {
+		"UserId",
+		"PackageLicenseId",
+	},
+	TableNameOauthTokens: {
+		"AppName",
+		"UserId",
+		"LastUsedDate",
+		"UseCount",
+	},
+	TableNameSetupEntityAccess: {
+		"ParentId",
+		"SetupEntityId",
+		"SetupEntityType",
+	},
 }
 
 type SalesforceQuery struct {
@@ -184,6 +283,23 @@ func (q *SalesforceQuery) WhereGT(field string, value string) *SalesforceQuery {
 	return q
 }
 
+func (q *SalesforceQuery) WhereIn(field string, values ...string) *SalesforceQuery {
+	args := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		args = append(args, value)
+	}
+	q.sb.Where(q.sb.In(field, args...))
+	return q
+}
+
+// WhereDatetimeGTE adds a datetime SOQL condition. SOQL datetime literals are
+// unquoted, so this can't go through the builder's escaping. Only use with
+// hardcoded field names — never with user-supplied input.
+func (q *SalesforceQuery) WhereDatetimeGTE(field string, value time.Time) *SalesforceQuery {
+	q.sb.Where(fmt.Sprintf("%s >= %s", field, value.UTC().Format(soqlDatetimeLayout)))
+	return q
+}
+
 func (q *SalesforceQuery) WhereInSubQuery(field string, sq *SalesforceQuery) *SalesforceQuery {
 	q.sb.Where(fmt.Sprintf("%s IN (%s)", field, sq.String()))
 	return q
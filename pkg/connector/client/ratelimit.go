@@ -1,8 +1,12 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -13,52 +17,193 @@ import (
 const (
 	RateLimitHeaderKey = "Sforce-Limit-Info"
 	RateLimitFmt       = "api-usage=%d/%d"
+
+	limitInfoAPIUsage       = "api-usage"
+	limitInfoPerAppAPIUsage = "per-app-api-usage"
 )
 
+// APIUsage is one used/limit pair reported in Sforce-Limit-Info.
+type APIUsage struct {
+	Used  int64
+	Limit int64
+}
+
+// Remaining is how many calls are left before the limit is reached.
+func (u *APIUsage) Remaining() int64 {
+	return max(u.Limit-u.Used, 0)
+}
+
+// LimitInfo is a parsed Sforce-Limit-Info header. Salesforce always reports
+// the org's API usage over a rolling 24 hours, and adds the connected app's
+// own usage when the app has a per-app limit:
+//
+//	Sforce-Limit-Info: api-usage=25/15000, per-app-api-usage=17/250(appName=sample-app)
+//
+// https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/headers_api_usage.htm
+type LimitInfo struct {
+	APIUsage       *APIUsage
+	PerAppAPIUsage *APIUsage
+	AppName        string
+}
+
+// ParseLimitInfo parses the value of a Sforce-Limit-Info header. Entries it
+// doesn't know are skipped, but at least one usage must be present.
+func ParseLimitInfo(value string) (*LimitInfo, error) {
+	info := &LimitInfo{}
+	for _, entry := range strings.Split(value, ",") {
+		key, rest, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case limitInfoAPIUsage:
+			usage, _, err := parseAPIUsage(rest)
+			if err != nil {
+				return nil, fmt.Errorf("baton-salesforce: invalid %s %q: %w", RateLimitHeaderKey, value, err)
+			}
+			info.APIUsage = usage
+		case limitInfoPerAppAPIUsage:
+			usage, suffix, err := parseAPIUsage(rest)
+			if err != nil {
+				return nil, fmt.Errorf("baton-salesforce: invalid %s %q: %w", RateLimitHeaderKey, value, err)
+			}
+			info.PerAppAPIUsage = usage
+			if appName, ok := strings.CutPrefix(suffix, "(appName="); ok {
+				info.AppName = strings.TrimSuffix(appName, ")")
+			}
+		}
+	}
+	if info.APIUsage == nil && info.PerAppAPIUsage == nil {
+		return nil, fmt.Errorf("baton-salesforce: no API usage in %s %q", RateLimitHeaderKey, value)
+	}
+	return info, nil
+}
+
+// parseAPIUsage reads "used/limit" off the front of s and returns whatever
+// follows the limit.
+func parseAPIUsage(s string) (*APIUsage, string, error) {
+	usedString, rest, found := strings.Cut(s, "/")
+	if !found {
+		return nil, "", fmt.Errorf("missing limit")
+	}
+	end := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+	if end == -1 {
+		end = len(rest)
+	}
+	used, err := strconv.ParseInt(usedString, 10, 64)
+	if err != nil {
+		return nil, "", err
+	}
+	limit, err := strconv.ParseInt(rest[:end], 10, 64)
+	if err != nil {
+		return nil, "", err
+	}
+	return &APIUsage{Used: used, Limit: limit}, rest[end:], nil
+}
+
+// RateLimitDescription describes whichever of the org and per-app limits has
+// fewer calls remaining.
+func (l *LimitInfo) RateLimitDescription() *v2.RateLimitDescription {
+	usage := l.APIUsage
+	if usage == nil || (l.PerAppAPIUsage != nil && l.PerAppAPIUsage.Remaining() < usage.Remaining()) {
+		usage = l.PerAppAPIUsage
+	}
+
+	description := &v2.RateLimitDescription{
+		Status:    v2.RateLimitDescription_STATUS_OK,
+		Limit:     usage.Limit,
+		Remaining: usage.Remaining(),
+		// The limit is moving a 24-hour window for total requests. It
+		// doesn't reset to 0. Whenever under limit, it can make more
+		// requests. When more requests are available is not tracked.
+		// https://developer.salesforce.com/docs/atlas.en-us.salesforce_app_limits_cheatsheet.meta/salesforce_app_limits_cheatsheet/salesforce_app_limits_platform_api.htm
+		ResetAt: nil,
+	}
+	if description.Remaining == 0 {
+		description.Status = v2.RateLimitDescription_STATUS_OVERLIMIT
+	}
+	return description
+}
+
 // RoundTrip - the simpleforce interface doesn't expose HTTP headers to us, but
 // we can still reach them if we create a wrapper to the `.RoundTrip()` method.
-// This just calls the original `httpClient.RoundTrip()` and caches the header
-// value that Salesforce uses to communicate remaining API call counts.
+// This just calls the original `httpClient.RoundTrip()` and records the header
+// value that Salesforce uses to communicate API usage. With an API budget set,
+// it also refuses requests once the budget is used up. A request rejected for
+// an expired session is sent once more with a new one.
 func (t *salesforceHttpTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	logger := ctxzap.Extract(request.Context())
+	var accessToken string
 	if t.tokenSource != nil {
 		token, err := t.tokenSource.Token()
 		if err != nil {
-			ctxzap.Extract(request.Context()).Warn("baton-salesforce: failed to refresh token, proceeding with existing headers", zap.Error(err))
+			logger.Warn("baton-salesforce: failed to refresh token, proceeding with existing headers", zap.Error(err))
 		} else {
+			accessToken = token.AccessToken
 			reqCopy := request.Clone(request.Context())
-			reqCopy.Header.Set("Authorization", "Bearer "+token.AccessToken)
+			reqCopy.Header.Set("Authorization", "Bearer "+accessToken)
 			request = reqCopy
 		}
 	}
 
-	t.rateLimit = nil // clear previous
+	if t.budget != nil && request.URL.Path != LimitsPath {
+		if t.budget.claimRefresh() {
+			t.pollLimits(request)
+		}
+		if err := t.budget.check(); err != nil {
+			return nil, err
+		}
+	}
+
 	response, err := t.base.RoundTrip(request)
 	if err != nil {
 		return response, err
 	}
+	if response.StatusCode == http.StatusUnauthorized && accessToken != "" {
+		response, err = t.renewSession(request, accessToken, response)
+		if err != nil {
+			return response, err
+		}
+	}
 
-	if rateLimitInfo, ok := response.Header[RateLimitHeaderKey]; ok && len(rateLimitInfo) == 1 {
-		var remaining int64
-		var limit int64
-		if found, err := fmt.Sscanf(rateLimitInfo[0], RateLimitFmt, &remaining, &limit); err == nil && found == 2 {
-			t.rateLimit = &v2.RateLimitDescription{
-				Status:    v2.RateLimitDescription_STATUS_OK,
-				Limit:     limit,
-				Remaining: remaining,
-				// The limit is moving a 24-hour window for total requests. It
-				// doesn't reset to 0. Whenever under limit, it can make more
-				// requests. When more requests are available is not tracked.
-				// https://developer.salesforce.com/docs/atlas.en-us.salesforce_app_limits_cheatsheet.meta/salesforce_app_limits_cheatsheet/salesforce_app_limits_platform_api.htm
-				ResetAt: nil,
-			}
-			if remaining > limit {
-				t.rateLimit.Status = v2.RateLimitDescription_STATUS_OVERLIMIT
-			}
+	if value := response.Header.Get(RateLimitHeaderKey); value != "" {
+		info, err := ParseLimitInfo(value)
+		if err != nil {
+			logger.Debug("baton-salesforce: ignoring unparseable limit header", zap.Error(err))
+			return response, nil
+		}
+		if info.APIUsage != nil && t.budget != nil {
+			t.budget.observe(info.APIUsage.Used, info.APIUsage.Limit)
+		}
+		if capture, ok := request.Context().Value(rateLimitCaptureKey{}).(*rateLimitCapture); ok {
+			capture.description.Store(info.RateLimitDescription())
 		}
 	}
 	return response, nil
 }
 
+// rateLimitCapture receives the rate limit of the responses to requests made
+// with its context, so concurrent calls each report the rate limit of their
+// own response rather than whichever response arrived last.
+type rateLimitCapture struct {
+	description atomic.Pointer[v2.RateLimitDescription]
+}
+
+type rateLimitCaptureKey struct{}
+
+// captureRateLimit returns a context whose requests report their rate limit
+// to the returned capture.
+func captureRateLimit(ctx context.Context) (context.Context, *rateLimitCapture) {
+	capture := &rateLimitCapture{}
+	return context.WithValue(ctx, rateLimitCaptureKey{}, capture), capture
+}
+
+// get returns the rate limit of the latest response, or nil if none carried
+// one (e.g. it was served from the cache).
+func (c *rateLimitCapture) get() *v2.RateLimitDescription {
+	return c.description.Load()
+}
+
 func WithRateLimitAnnotations(
 	ratelimitDescriptionAnnotations ...*v2.RateLimitDescription,
 ) annotations.Annotations {
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimitInfo(t *testing.T) {
+	t.Run("should read the org usage", func(t *testing.T) {
+		info, err := ParseLimitInfo("api-usage=1250/15000")
+		require.NoError(t, err)
+		require.Equal(t, &APIUsage{Used: 1250, Limit: 15000}, info.APIUsage)
+		require.Nil(t, info.PerAppAPIUsage)
+
+		description := info.RateLimitDescription()
+		require.Equal(t, v2.RateLimitDescription_STATUS_OK, description.Status)
+		require.Equal(t, int64(15000), description.Limit)
+		require.Equal(t, int64(13750), description.Remaining)
+	})
+
+	t.Run("should read the per-app usage and report the tighter limit", func(t *testing.T) {
+		info, err := ParseLimitInfo("api-usage=25/15000, per-app-api-usage=240/250(appName=sample-app)")
+		require.NoError(t, err)
+		require.Equal(t, &APIUsage{Used: 25, Limit: 15000}, info.APIUsage)
+		require.Equal(t, &APIUsage{Used: 240, Limit: 250}, info.PerAppAPIUsage)
+		require.Equal(t, "sample-app", info.AppName)
+
+		description := info.RateLimitDescription()
+		require.Equal(t, int64(250), description.Limit)
+		require.Equal(t, int64(10), description.Remaining)
+	})
+
+	t.Run("should report over limit once nothing remains", func(t *testing.T) {
+		info, err := ParseLimitInfo("api-usage=15010/15000")
+		require.NoError(t, err)
+		description := info.RateLimitDescription()
+		require.Equal(t, v2.RateLimitDescription_STATUS_OVERLIMIT, description.Status)
+		require.Equal(t, int64(0), description.Remaining)
+	})
+
+	t.Run("should reject headers without usage", func(t *testing.T) {
+		for _, value := range []string{"", "api-usage", "api-usage=12", "api-usage=a/15000", "other=1/2"} {
+			_, err := ParseLimitInfo(value)
+			require.Error(t, err, value)
+		}
+	})
+}
+
+func TestTransportRateLimit(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+		// Report a different usage per query so that each caller can tell
+		// whether it got the rate limit of its own response.
+		used := usedPattern.FindStringSubmatch(r.URL.Query().Get("q"))[1]
+		w.Header().Set(RateLimitHeaderKey, fmt.Sprintf("api-usage=%s/15000", used))
+		writeJSON(t, w, map[string]any{"totalSize": 0, "done": true, "records": []any{}})
+	})
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			query := NewQuery(TableNameUsers).WhereEq("Id", strconv.Itoa(100+i))
+			_, _, ratelimitData, err := c.query(ctx, query, "", PageSizeDefault)
+			require.NoError(t, err)
+			require.Equal(t, int64(15000-100-i), ratelimitData.Remaining)
+		}()
+	}
+	wg.Wait()
+}
+
+var usedPattern = regexp.MustCompile(`Id = '(\d+)'`)
@@ -4,34 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/conductorone/simpleforce"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
 )
 
-var ErrObjectNotFound = errors.New("salesforce object does not exist")
-var ErrObjectAlreadyExists = errors.New("salesforce object already exists")
-var ErrRoleAlreadyCleared = errors.New("salesforce territory role is already empty")
-var ErrRoleMismatch = errors.New("salesforce territory role does not match expected role")
-
-func isSalesforceDuplicateError(err error) bool {
-	var sfErr simpleforce.SalesforceError
-	return errors.As(err, &sfErr) && sfErr.ErrorCode == "DUPLICATE_VALUE"
-}
-
-// isSObjectNotSupportedError reports whether the error is Salesforce's
-// INVALID_TYPE, returned when an SObject does not exist in the org (e.g. a
-// SELECT ... FROM BotDefinition against an org without Agentforce or Einstein
-// Bots enabled). Callers use this to skip an optional SObject gracefully
-// instead of failing the sync.
-func isSObjectNotSupportedError(err error) bool {
-	var sfErr simpleforce.SalesforceError
-	return errors.As(err, &sfErr) && sfErr.ErrorCode == "INVALID_TYPE"
-}
-
 func getQueryString(
 	q *SalesforceQuery,
 	paginationPath string,
@@ -61,30 +44,56 @@ func (c *SalesforceClient) query(
 	string,
 	*v2.RateLimitDescription,
 	error,
+) {
+	records, ratelimitData, err := c.queryResult(ctx, query, paginationPath, pageSize)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+	return records.Records, nextRecordsToken(records), ratelimitData, nil
+}
+
+// queryResult is query without the unpacking, for callers that need the
+// TotalSize Salesforce reports alongside the first page.
+func (c *SalesforceClient) queryResult(
+	ctx context.Context,
+	query *SalesforceQuery,
+	paginationPath string,
+	pageSize int,
+) (
+	*simpleforce.QueryResult,
+	*v2.RateLimitDescription,
+	error,
 ) {
 	err := c.Initialize(ctx)
 	if err != nil {
-		return nil, "", nil, err
+		return nil, nil, err
 	}
 
 	logger := ctxzap.Extract(ctx)
 	queryString := getQueryString(query, paginationPath, pageSize)
-	records, err := c.client.Query(ctx, queryString)
-	ratelimitData := c.salesforceTransport.rateLimit
+	var records *simpleforce.QueryResult
+	ratelimitData, err := withRetry(ctx, "query", func() (*v2.RateLimitDescription, error) {
+		ctx, rateLimit := captureRateLimit(ctx)
+		var err error
+		records, err = c.client.Query(ctx, queryString)
+		return rateLimit.get(), classifyError(err)
+	})
 	if err != nil {
 		logger.Error(
 			"salesforce-connector: error querying salesforce",
 			zap.String("query", queryString),
 			zap.Error(err),
 		)
-		return nil, "", ratelimitData, err
+		return nil, ratelimitData, err
 	}
+	return records, ratelimitData, nil
+}
 
-	nextToken := ""
-	if !records.Done {
-		nextToken = records.NextRecordsURL
+func nextRecordsToken(records *simpleforce.QueryResult) string {
+	if records.Done {
+		return ""
 	}
-	return records.Records, nextToken, ratelimitData, nil
+	return records.NextRecordsURL
 }
 
 // queryWithAPIVersion behaves like query but pins the SOQL request to a specific
@@ -122,13 +131,18 @@ func (c *SalesforceClient) queryWithAPIVersion(
 		queryString = u.String()
 	}
 
-	records, err := c.client.Query(ctx, queryString)
-	ratelimitData := c.salesforceTransport.rateLimit
+	var records *simpleforce.QueryResult
+	ratelimitData, err := withRetry(ctx, "query", func() (*v2.RateLimitDescription, error) {
+		ctx, rateLimit := captureRateLimit(ctx)
+		var err error
+		records, err = c.client.Query(ctx, queryString)
+		return rateLimit.get(), classifyError(err)
+	})
 	if err != nil {
 		// INVALID_TYPE (e.g. BotDefinition on an org without Agentforce) is expected,
 		// so log it at Debug instead of Error. The error is still returned either way;
 		// the caller detects it and decides whether to treat it as a real failure.
-		if isSObjectNotSupportedError(err) {
+		if errors.Is(err, ErrSObjectNotSupported) {
 			logger.Debug("salesforce-connector: SObject not supported", zap.String("query", queryString), zap.Error(err))
 		} else {
 			logger.Error("salesforce-connector: error querying salesforce", zap.String("query", queryString), zap.Error(err))
@@ -136,11 +150,64 @@ func (c *SalesforceClient) queryWithAPIVersion(
 		return nil, "", ratelimitData, fmt.Errorf("baton-salesforce: query failed (API v%s): %w", apiVersion, err)
 	}
 
-	nextToken := ""
-	if !records.Done {
-		nextToken = records.NextRecordsURL
+	return records.Records, nextRecordsToken(records), ratelimitData, nil
+}
+
+// restRequest sends a request to a REST path on the instance URL. It goes
+// through the same intercepted transport as simpleforce, so the bearer token
+// and the rate-limit header are handled in one place, but unlike ApexREST it
+// returns the raw response so callers can read headers and non-JSON bodies.
+// Salesforce error bodies on non-2xx responses are parsed into a
+// simpleforce.SalesforceError, joined onto the returned error and classified.
+func (c *SalesforceClient) restRequest(
+	ctx context.Context,
+	method string,
+	path string,
+	body any,
+	options ...uhttp.DoOption,
+) (
+	*http.Response,
+	*v2.RateLimitDescription,
+	error,
+) {
+	err := c.Initialize(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u, err := url.Parse(c.client.GetLoc() + path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requestOptions := []uhttp.RequestOption{
+		uhttp.WithBearerToken(c.client.GetSid()),
+		// Job state and result pages change between calls; never serve them
+		// from the GET cache.
+		uhttp.WithNoCache(),
 	}
-	return records.Records, nextToken, ratelimitData, nil
+	if body != nil {
+		requestOptions = append(requestOptions, uhttp.WithJSONBody(body))
+	}
+
+	request, err := c.httpClient.NewRequest(ctx, method, u, requestOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, rateLimit := captureRateLimit(ctx)
+	response, err := c.httpClient.Do(request.WithContext(ctx), options...)
+	ratelimitData := rateLimit.get()
+	if err != nil {
+		if response != nil && (response.StatusCode < 200 || response.StatusCode > 299) {
+			responseBody, readErr := io.ReadAll(response.Body)
+			if readErr == nil {
+				err = errors.Join(err, salesforceErrorFromBody(response.StatusCode, responseBody))
+			}
+		}
+		return response, ratelimitData, classifyError(err)
+	}
+	return response, ratelimitData, nil
 }
 
 func (c *SalesforceClient) getSObject(
@@ -179,6 +246,8 @@ func (c *SalesforceClient) getSObject(
 
 // CreateObject this call to simpleforce is broken out into a helper function so
 // that we can always ensure that the client is initialized.
+// Like the other single-record helpers and query, it retries when the row is
+// locked or Salesforce returns a 5xx.
 func (c *SalesforceClient) CreateObject(
 	ctx context.Context,
 	tableName string,
@@ -195,12 +264,17 @@ func (c *SalesforceClient) CreateObject(
 		return nil, err
 	}
 
-	created := c.client.SObject(tableName)
+	obj := c.client.SObject(tableName)
 	for key, value := range values {
-		created = created.Set(key, value)
+		obj = obj.Set(key, value)
 	}
-	created, err = created.Create(ctx)
-	ratelimitData := c.salesforceTransport.rateLimit
+	var created *simpleforce.SObject
+	ratelimitData, err := withRetryIf(ctx, "create "+tableName, isRetryableCreateError, func() (*v2.RateLimitDescription, error) {
+		ctx, rateLimit := captureRateLimit(ctx)
+		var err error
+		created, err = obj.Create(ctx)
+		return rateLimit.get(), classifyError(err)
+	})
 	if err != nil {
 		return ratelimitData, err
 	}
@@ -234,8 +308,11 @@ func (c *SalesforceClient) UpdateObject(
 	for key, value := range values {
 		obj = obj.Set(key, value)
 	}
-	_, err = obj.Update(ctx)
-	ratelimitData := c.salesforceTransport.rateLimit
+	ratelimitData, err := withRetry(ctx, "update "+tableName, func() (*v2.RateLimitDescription, error) {
+		ctx, rateLimit := captureRateLimit(ctx)
+		_, err := obj.Update(ctx)
+		return rateLimit.get(), classifyError(err)
+	})
 	if err != nil {
 		return ratelimitData, fmt.Errorf("baton-salesforce: failed to update %s: %w", tableName, err)
 	}
@@ -260,13 +337,14 @@ func (c *SalesforceClient) DeleteObject(
 
 	// TODO(marcos): There is a bug in simpleforce that prevents us from doing
 	// `found.Delete()`. See https://github.com/simpleforce/simpleforce/pull/44.
-	err = c.client.
+	obj := c.client.
 		SObject(tableName).
-		Set("Id", id).
-		Delete(ctx)
-
-	ratelimitData := c.salesforceTransport.rateLimit
-	return ratelimitData, err
+		Set("Id", id)
+	return withRetry(ctx, "delete "+tableName, func() (*v2.RateLimitDescription, error) {
+		ctx, rateLimit := captureRateLimit(ctx)
+		err := obj.Delete(ctx)
+		return rateLimit.get(), classifyError(err)
+	})
 }
 
 func (c *SalesforceClient) getOneUser(ctx context.Context, userId string) (
@@ -278,14 +356,15 @@ func (c *SalesforceClient) getOneUser(ctx context.Context, userId string) (
 	if err != nil {
 		return nil, nil, err
 	}
+	ctx, rateLimit := captureRateLimit(ctx)
 	user, err := c.client.
 		SObject(TableNameUsers).
 		Get(ctx, userId)
+	ratelimitData := rateLimit.get()
 	if err != nil {
-		return nil, nil, err
+		return nil, ratelimitData, classifyError(err)
 	}
 
-	ratelimitData := c.salesforceTransport.rateLimit
 	if user == nil {
 		return nil, ratelimitData, fmt.Errorf("missing user %s", userId)
 	}
@@ -301,10 +380,11 @@ func (c *SalesforceClient) updateUser(
 	*v2.RateLimitDescription,
 	error,
 ) {
+	ctx, rateLimit := captureRateLimit(ctx)
 	user, err := user.Set(fieldName, value).Update(ctx)
-	ratelimitData := c.salesforceTransport.rateLimit
+	ratelimitData := rateLimit.get()
 	if err != nil {
-		return ratelimitData, err
+		return ratelimitData, classifyError(err)
 	}
 
 	if user == nil {
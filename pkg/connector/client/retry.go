@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	retryMaxAttempts = 4
+	retryMaxDelay    = 8 * time.Second
+	// retryReservedCalls is how much of the org's daily API allowance retries
+	// leave alone, so a burst of lock contention can't use up what's left.
+	retryReservedCalls = 1000
+)
+
+// retryBaseDelay is a var so tests can shorten it.
+var retryBaseDelay = 500 * time.Millisecond
+
+// isRetryableError reports whether the request may succeed if sent again:
+// another transaction held a lock on the row, or Salesforce returned a 5xx.
+func isRetryableError(err error) bool {
+	return errors.Is(err, ErrRowLocked) || errors.Is(err, ErrServerUnavailable)
+}
+
+// isRetryableCreateError is isRetryableError for requests that create a
+// record. A 5xx can come back after Salesforce saved the record, so sending
+// it again could create a duplicate. A locked row means nothing was saved.
+func isRetryableCreateError(err error) bool {
+	return errors.Is(err, ErrRowLocked)
+}
+
+// hasRetryBudget reports whether the org has enough of its daily API allowance
+// left to spend a call on a retry. A response without Sforce-Limit-Info is
+// treated as having budget.
+func hasRetryBudget(ratelimitData *v2.RateLimitDescription) bool {
+	if ratelimitData == nil || ratelimitData.Limit == 0 {
+		return true
+	}
+	if ratelimitData.Status == v2.RateLimitDescription_STATUS_OVERLIMIT {
+		return false
+	}
+	return ratelimitData.Remaining > retryReservedCalls
+}
+
+// retryDelay is the full-jitter exponential backoff before the given retry,
+// counting from 0.
+func retryDelay(retry int) time.Duration {
+	ceiling := min(retryBaseDelay<<retry, retryMaxDelay)
+	return rand.N(ceiling) + 1
+}
+
+// withRetry calls do until it succeeds, fails with an error that isn't
+// retryable, runs out of attempts, or the org runs low on API calls. It
+// returns the rate limit data and error of the last attempt.
+func withRetry(
+	ctx context.Context,
+	operation string,
+	do func() (*v2.RateLimitDescription, error),
+) (*v2.RateLimitDescription, error) {
+	return withRetryIf(ctx, operation, isRetryableError, do)
+}
+
+// withRetryIf is withRetry with the caller deciding which errors are retried.
+func withRetryIf(
+	ctx context.Context,
+	operation string,
+	isRetryable func(error) bool,
+	do func() (*v2.RateLimitDescription, error),
+) (*v2.RateLimitDescription, error) {
+	logger := ctxzap.Extract(ctx)
+	for attempt := 0; ; attempt++ {
+		ratelimitData, err := do()
+		if err == nil || !isRetryable(err) {
+			return ratelimitData, err
+		}
+		if attempt+1 >= retryMaxAttempts || !hasRetryBudget(ratelimitData) {
+			return ratelimitData, err
+		}
+
+		delay := retryDelay(attempt)
+		logger.Debug(
+			"salesforce-client: retrying request",
+			zap.String("operation", operation),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return ratelimitData, errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = 500 * time.Millisecond })
+
+	lockedRow := func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`[{"message":"unable to obtain exclusive access to this record","errorCode":"UNABLE_TO_LOCK_ROW"}]`))
+	}
+
+	t.Run("should retry a locked row until it succeeds", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			attempts++
+			if attempts < 3 {
+				lockedRow(w)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		_, err := c.DeleteObject(ctx, TableNameGroupMemberships, "011A")
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("should retry a 5xx and give up after the last attempt", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		_, err := c.UpdateObject(ctx, TableNameUsers, "005A", map[string]interface{}{"IsActive": false})
+		require.ErrorIs(t, err, ErrServerUnavailable)
+		require.Equal(t, retryMaxAttempts, attempts)
+	})
+
+	t.Run("should not retry a create that may have been saved", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		_, err := c.CreateObject(ctx, TableNameGroupMemberships, map[string]interface{}{"GroupId": "00GA"})
+		require.ErrorIs(t, err, ErrServerUnavailable)
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("should retry a create that hit a locked row", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 2 {
+				lockedRow(w)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"011A","success":true,"errors":[]}`))
+		})
+
+		_, err := c.CreateObject(ctx, TableNameGroupMemberships, map[string]interface{}{"GroupId": "00GA"})
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+	})
+
+	t.Run("should not retry other errors", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`[{"message":"duplicate value found","errorCode":"DUPLICATE_VALUE"}]`))
+		})
+
+		_, err := c.CreateObject(ctx, TableNameGroupMemberships, map[string]interface{}{"GroupId": "00GA"})
+		require.ErrorIs(t, err, ErrObjectAlreadyExists)
+		require.Equal(t, 1, attempts)
+	})
+}
+
+func TestHasRetryBudget(t *testing.T) {
+	require.True(t, hasRetryBudget(nil))
+	require.True(t, hasRetryBudget(&v2.RateLimitDescription{}))
+	require.True(t, hasRetryBudget(&v2.RateLimitDescription{Limit: 15000, Remaining: 14000}))
+	require.False(t, hasRetryBudget(&v2.RateLimitDescription{Limit: 15000, Remaining: retryReservedCalls}))
+	require.False(t, hasRetryBudget(&v2.RateLimitDescription{
+		Status: v2.RateLimitDescription_STATUS_OVERLIMIT,
+		Limit:  15000,
+	}))
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
@@ -22,6 +25,7 @@ const (
 	PageSizeDefault    = 100
 	SalesforceClientID = "ConductorOne"
 	GroupIDPrefix      = "00G"
+	GroupTypeQueue     = "Queue"
 	UserIDPrefix       = "005"
 
 	trueConst = "true"
@@ -35,7 +39,14 @@ type SalesforceClient struct {
 	Username            string
 	password            string
 	securityToken       string
-	initialized         bool
+	httpClient          *uhttp.BaseHttpClient
+	bulkQueryThreshold  int
+	apiBudgetPercent    int
+
+	// initMu serializes Initialize until it first succeeds; initialized lets
+	// every later call skip the lock.
+	initMu      sync.Mutex
+	initialized atomic.Bool
 }
 
 // Gathered from the UserType field found here:
@@ -50,8 +61,8 @@ var userTypesToSkip = map[string]bool{
 
 type salesforceHttpTransport struct {
 	base        http.RoundTripper
-	rateLimit   *v2.RateLimitDescription
 	tokenSource oauth2.TokenSource
+	budget      *apiBudget
 }
 
 func New(
@@ -70,11 +81,33 @@ func New(
 	}
 }
 
+// SetBulkQueryThreshold enables the Bulk API 2.0 query path for the large
+// listing calls (users and assignments) once a query matches more than
+// threshold rows. Zero or a negative value keeps every query on REST.
+func (c *SalesforceClient) SetBulkQueryThreshold(threshold int) {
+	c.bulkQueryThreshold = threshold
+}
+
+// SetAPIBudget caps the share of the org's daily API request limit the
+// connector uses, as a percentage. Zero or a negative value removes the cap.
+func (c *SalesforceClient) SetAPIBudget(percent int) {
+	c.apiBudgetPercent = percent
+}
+
+// Initialize sets up the simpleforce client and logs in. It is safe to call
+// concurrently: the first successful call does the work and every later call
+// returns immediately, while a failed call leaves the next one to try again.
 func (c *SalesforceClient) Initialize(ctx context.Context) error {
-	logger := ctxzap.Extract(ctx)
-	if c.initialized {
+	if c.initialized.Load() {
 		return nil
 	}
+	c.initMu.Lock()
+	defer c.initMu.Unlock()
+	if c.initialized.Load() {
+		return nil
+	}
+
+	logger := ctxzap.Extract(ctx)
 	logger.Debug("Initializing Salesforce client")
 
 	simpleClient, err := simpleforce.NewClient(
@@ -105,52 +138,58 @@ func (c *SalesforceClient) Initialize(ctx context.Context) error {
 		)
 		return err
 	}
-	interceptedTransport := salesforceHttpTransport{
-		base:        httpClient.Transport,
-		rateLimit:   &v2.RateLimitDescription{},
-		tokenSource: c.TokenSource,
-	}
-
-	httpClient.Transport = &interceptedTransport
-	wrapper, err := uhttp.NewBaseHttpClientWithContext(ctx, httpClient)
-	if err != nil {
-		return fmt.Errorf("creating HTTP wrapper failed: %w", err)
-	}
-
-	simpleClient.SetHttpClient(wrapper)
-
-	// OAuth token source takes precedence over username/password.
-	if c.TokenSource != nil {
+	// OAuth token source takes precedence over username/password. Either way
+	// the transport sends the current token with every request, and renews it
+	// when Salesforce reports the session expired.
+	tokenSource := c.TokenSource
+	if tokenSource != nil {
 		logger.Debug("Salesforce client using token source")
-		token, err := c.TokenSource.Token()
-		if err != nil {
-			return fmt.Errorf("baton-salesforce: failed to get token: %w", err)
-		}
-		instanceURL := c.baseUrl
-		if v, ok := token.Extra("instance_url").(string); ok && v != "" {
-			instanceURL = v
-		}
-		// SetSidLoc requires a non-empty session ID to mark the client as
-		// authenticated. The transport injects the real Bearer token on every
-		// request, so the value here is only used to satisfy simpleforce's
-		// internal isLoggedIn() check.
-		simpleClient.SetSidLoc(token.AccessToken, instanceURL)
 	} else {
 		logger.Debug("Salesforce client using username and password")
-		err = simpleClient.LoginPassword(
+		tokenSource, err = newPasswordTokenSource(
 			ctx,
+			c.baseUrl,
 			c.Username,
 			c.password,
 			c.securityToken,
 		)
 		if err != nil {
-			logger.Error("could not login", zap.Error(err))
 			return err
 		}
 	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return fmt.Errorf("baton-salesforce: failed to get token: %w", err)
+	}
+	instanceURL := c.baseUrl
+	if v, ok := token.Extra("instance_url").(string); ok && v != "" {
+		instanceURL = v
+	}
+	// SetSidLoc requires a non-empty session ID to mark the client as
+	// authenticated. The transport injects the real Bearer token on every
+	// request, so the value here is only used to satisfy simpleforce's
+	// internal isLoggedIn() check.
+	simpleClient.SetSidLoc(token.AccessToken, instanceURL)
+
+	interceptedTransport := salesforceHttpTransport{
+		base:        httpClient.Transport,
+		tokenSource: tokenSource,
+	}
+	if c.apiBudgetPercent > 0 {
+		interceptedTransport.budget = newAPIBudget(c.apiBudgetPercent)
+	}
+
+	httpClient.Transport = &interceptedTransport
+	wrapper, err := uhttp.NewBaseHttpClientWithContext(ctx, httpClient)
+	if err != nil {
+		return fmt.Errorf("creating HTTP wrapper failed: %w", err)
+	}
+
+	simpleClient.SetHttpClient(wrapper)
 	c.client = simpleClient
+	c.httpClient = wrapper
 	c.salesforceTransport = &interceptedTransport
-	c.initialized = true
+	c.initialized.Store(true)
 	return nil
 }
 
@@ -166,15 +205,16 @@ func (c *SalesforceClient) Ping(ctx context.Context) (
 		return nil, fmt.Errorf("salesforce-connector: failed to initialize client: %w", err)
 	}
 
+	ctx, rateLimit := captureRateLimit(ctx)
 	_, err = c.client.ApexREST(
 		ctx,
 		http.MethodGet,
 		LimitsPath,
 		nil,
 	)
-	ratelimitData := c.salesforceTransport.rateLimit
+	ratelimitData := rateLimit.get()
 	if err != nil {
-		return ratelimitData, fmt.Errorf("salesforce-connector: error validating credentials: %w", err)
+		return ratelimitData, fmt.Errorf("salesforce-connector: error validating credentials: %w", classifyError(err))
 	}
 
 	return ratelimitData, nil
@@ -216,6 +256,28 @@ func getBoolField(record simpleforce.SObject, field string) (bool, error) {
 	}
 }
 
+// getIntField reads a number field, treating a missing value as zero.
+func getIntField(record simpleforce.SObject, field string) (int64, error) {
+	value := record.InterfaceField(field)
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("salesforce-connector: unexpected field %s type, %s", field, value)
+	}
+}
+
 func shouldSkipSyncingUserType(
 	ctx context.Context,
 	user simpleforce.SObject,
@@ -283,7 +345,7 @@ func (c *SalesforceClient) GetUsers(
 		// See https://developer.salesforce.com/docs/atlas.en-us.object_reference.meta/object_reference/sforce_api_objects_user.htm
 		query = NewQuery(TableNameUsers).WhereEq("UserType", "Standard")
 	}
-	records, paginationUrl, ratelimitData, err := c.query(
+	records, paginationUrl, ratelimitData, err := c.queryLarge(
 		ctx,
 		query,
 		pageToken,
@@ -318,6 +380,8 @@ func (c *SalesforceClient) GetUsers(
 			LastName:             record.StringField("LastName"),
 			UserType:             record.StringField("UserType"),
 			LicenseDefinitionKey: licenseDefinitionKey(record),
+			ProfileID:            record.StringField("ProfileId"),
+			UserRoleID:           record.StringField("UserRoleId"),
 			IsActive:             isActive,
 			LastLoginDate:        lastLogin,
 		})
@@ -367,7 +431,7 @@ func (c *SalesforceClient) GetProfileByName(ctx context.Context, name string) (*
 	}, ratelimitData, nil
 }
 
-// GetUserRoles - SELECT Id, Name FROM UserRole.
+// GetUserRoles - SELECT Id, Name, ParentRoleId FROM UserRole.
 func (c *SalesforceClient) GetUserRoles(
 	ctx context.Context,
 	pageToken string,
@@ -391,8 +455,9 @@ func (c *SalesforceClient) GetUserRoles(
 	roles := make([]*SalesforceRole, 0)
 	for _, record := range records {
 		roles = append(roles, &SalesforceRole{
-			ID:   record.ID(),
-			Name: record.StringField("Name"),
+			ID:           record.ID(),
+			Name:         record.StringField("Name"),
+			ParentRoleID: record.StringField("ParentRoleId"),
 		})
 	}
 	return roles, paginationUrl, ratelimitData, nil
@@ -413,11 +478,13 @@ func getGroupName(ctx context.Context, record simpleforce.SObject) string {
 	return name
 }
 
-// GetGroups -.
+// GetGroups -. With excludeQueues set, queues are left out so they can be
+// synced on their own.
 func (c *SalesforceClient) GetGroups(
 	ctx context.Context,
 	pageToken string,
 	pageSize int,
+	excludeQueues bool,
 ) (
 	[]*SalesforceGroup,
 	string,
@@ -425,6 +492,37 @@ func (c *SalesforceClient) GetGroups(
 	error,
 ) {
 	query := NewQuery(TableNameGroups)
+	if excludeQueues {
+		query = query.WhereNotEq("Type", GroupTypeQueue)
+	}
+	return c.getGroups(ctx, query, pageToken, pageSize)
+}
+
+// GetQueues lists the groups of type Queue.
+func (c *SalesforceClient) GetQueues(
+	ctx context.Context,
+	pageToken string,
+	pageSize int,
+) (
+	[]*SalesforceGroup,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	return c.getGroups(ctx, NewQuery(TableNameGroups).WhereEq("Type", GroupTypeQueue), pageToken, pageSize)
+}
+
+func (c *SalesforceClient) getGroups(
+	ctx context.Context,
+	query *SalesforceQuery,
+	pageToken string,
+	pageSize int,
+) (
+	[]*SalesforceGroup,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
 	records, paginationUrl, ratelimitData, err := c.query(
 		ctx,
 		query,
@@ -449,6 +547,68 @@ func (c *SalesforceClient) GetGroups(
 	return groups, paginationUrl, ratelimitData, nil
 }
 
+// GetQueueSobjectTypes returns the object types (Case, Lead, ...) each of the
+// given queues can own records of, keyed by queue ID.
+func (c *SalesforceClient) GetQueueSobjectTypes(
+	ctx context.Context,
+	queueIDs []string,
+) (
+	map[string][]string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	sobjectTypes := make(map[string][]string, len(queueIDs))
+	if len(queueIDs) == 0 {
+		return sobjectTypes, nil, nil
+	}
+
+	query := NewQuery(TableNameQueueSobjects).WhereIn("QueueId", queueIDs...)
+	var ratelimitData *v2.RateLimitDescription
+	pageToken := ""
+	for {
+		var records []simpleforce.SObject
+		var err error
+		records, pageToken, ratelimitData, err = c.query(ctx, query, pageToken, PageSizeDefault)
+		if err != nil {
+			return nil, ratelimitData, err
+		}
+		for _, record := range records {
+			queueID := record.StringField("QueueId")
+			sobjectTypes[queueID] = append(sobjectTypes[queueID], record.StringField("SobjectType"))
+		}
+		if pageToken == "" {
+			return sobjectTypes, ratelimitData, nil
+		}
+	}
+}
+
+// GetQueueIDs returns which of the given group IDs belong to queues.
+func (c *SalesforceClient) GetQueueIDs(
+	ctx context.Context,
+	groupIDs []string,
+) (
+	map[string]bool,
+	*v2.RateLimitDescription,
+	error,
+) {
+	queueIDs := make(map[string]bool)
+	if len(groupIDs) == 0 {
+		return queueIDs, nil, nil
+	}
+
+	query := NewIDQuery(TableNameGroups).
+		WhereEq("Type", GroupTypeQueue).
+		WhereIn(SalesforcePK, groupIDs...)
+	records, _, ratelimitData, err := c.query(ctx, query, "", len(groupIDs))
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	for _, record := range records {
+		queueIDs[record.ID()] = true
+	}
+	return queueIDs, ratelimitData, nil
+}
+
 func getPermissionSetName(ctx context.Context, record simpleforce.SObject) string {
 	var name string
 	profile := record.SObjectField(ctx, "Profile", "Profile")
@@ -461,21 +621,54 @@ func getPermissionSetName(ctx context.Context, record simpleforce.SObject) strin
 	return name
 }
 
+// systemPermissionFields returns the PermissionSet columns backing the given
+// system permissions, e.g. PermissionsModifyAllData for ModifyAllData.
+func systemPermissionFields(systemPermissions []string) []string {
+	fields := make([]string, 0, len(systemPermissions))
+	for _, name := range systemPermissions {
+		fields = append(fields, systemPermissionFieldPrefix+name)
+	}
+	return fields
+}
+
+// getSystemPermissions returns which of the given system permissions are
+// turned on in the record.
+func getSystemPermissions(record simpleforce.SObject, systemPermissions []string) ([]string, error) {
+	enabled := make([]string, 0)
+	for _, name := range systemPermissions {
+		isEnabled, err := getBoolField(record, systemPermissionFieldPrefix+name)
+		if err != nil {
+			return nil, err
+		}
+		if isEnabled {
+			enabled = append(enabled, name)
+		}
+	}
+	return enabled, nil
+}
+
 // GetPermissionSets - select Id, Name, Label, Type From PermissionSet  and exclude "profile" type
 // Some permission sets are roles and have an id to a role id
 // query via Select Id, Name From Profile Where Id In (SELECT ProfileId From PermissionSet)
 // also there can be multiple permission sets with the same profile id.
+// The given system permissions are selected too, and the ones each permission
+// set turns on are returned with it.
 func (c *SalesforceClient) GetPermissionSets(
 	ctx context.Context,
 	pageToken string,
 	pageSize int,
+	systemPermissions []string,
 ) (
 	[]*SalesforcePermission,
 	string,
 	*v2.RateLimitDescription,
 	error,
 ) {
-	query := NewQuery(TableNamePermissionsSets)
+	fields := append(
+		slices.Clone(TableNamesToFieldsMapping[TableNamePermissionsSets]),
+		systemPermissionFields(systemPermissions)...,
+	)
+	query := NewQuery(TableNamePermissionsSets, fields...)
 	records, paginationUrl, ratelimitData, err := c.query(
 		ctx,
 		query,
@@ -488,18 +681,125 @@ func (c *SalesforceClient) GetPermissionSets(
 
 	permissions := make([]*SalesforcePermission, 0)
 	for _, record := range records {
+		enabled, err := getSystemPermissions(record, systemPermissions)
+		if err != nil {
+			return nil, "", ratelimitData, err
+		}
 		permissionSet := &SalesforcePermission{
-			ID:        record.ID(),
-			Name:      getPermissionSetName(ctx, record),
-			Label:     record.StringField("Label"),
-			Type:      record.StringField("Type"),
-			ProfileID: record.StringField("ProfileId"),
+			ID:                record.ID(),
+			Name:              getPermissionSetName(ctx, record),
+			Label:             record.StringField("Label"),
+			Type:              record.StringField("Type"),
+			ProfileID:         record.StringField("ProfileId"),
+			SystemPermissions: enabled,
 		}
 		permissions = append(permissions, permissionSet)
 	}
 	return permissions, paginationUrl, ratelimitData, nil
 }
 
+// GetObjectPermissions - SELECT Id, SobjectType, Permissions... FROM
+// ObjectPermissions WHERE ParentId = permissionSetID.
+func (c *SalesforceClient) GetObjectPermissions(
+	ctx context.Context,
+	permissionSetID string,
+	pageToken string,
+	pageSize int,
+) (
+	[]*ObjectPermission,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNameObjectPermissions).WhereEq("ParentId", permissionSetID)
+	records, paginationUrl, ratelimitData, err := c.query(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+
+	permissions := make([]*ObjectPermission, 0, len(records))
+	for _, record := range records {
+		permission := &ObjectPermission{
+			ID:              record.ID(),
+			PermissionSetID: record.StringField("ParentId"),
+			SobjectType:     record.StringField("SobjectType"),
+		}
+		for field, value := range map[string]*bool{
+			"PermissionsRead":             &permission.Read,
+			"PermissionsCreate":           &permission.Create,
+			"PermissionsEdit":             &permission.Edit,
+			"PermissionsDelete":           &permission.Delete,
+			"PermissionsViewAllRecords":   &permission.ViewAllRecords,
+			"PermissionsModifyAllRecords": &permission.ModifyAllRecords,
+		} {
+			*value, err = getBoolField(record, field)
+			if err != nil {
+				return nil, "", ratelimitData, err
+			}
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, paginationUrl, ratelimitData, nil
+}
+
+// GetFieldPermissions - SELECT Id, Field, PermissionsRead, PermissionsEdit
+// FROM FieldPermissions WHERE ParentId IN permissionSetIDs AND Field IN
+// fields. Fields are qualified with their object, e.g. Contact.SSN__c.
+func (c *SalesforceClient) GetFieldPermissions(
+	ctx context.Context,
+	permissionSetIDs []string,
+	fields []string,
+) (
+	[]*FieldPermission,
+	*v2.RateLimitDescription,
+	error,
+) {
+	permissions := make([]*FieldPermission, 0)
+	if len(permissionSetIDs) == 0 || len(fields) == 0 {
+		return permissions, nil, nil
+	}
+
+	query := NewQuery(TableNameFieldPermissions).
+		WhereIn("ParentId", permissionSetIDs...).
+		WhereIn("Field", fields...)
+	var ratelimitData *v2.RateLimitDescription
+	pageToken := ""
+	for {
+		var records []simpleforce.SObject
+		var err error
+		records, pageToken, ratelimitData, err = c.query(ctx, query, pageToken, PageSizeDefault)
+		if err != nil {
+			return nil, ratelimitData, err
+		}
+		for _, record := range records {
+			read, err := getBoolField(record, "PermissionsRead")
+			if err != nil {
+				return nil, ratelimitData, err
+			}
+			edit, err := getBoolField(record, "PermissionsEdit")
+			if err != nil {
+				return nil, ratelimitData, err
+			}
+			permissions = append(permissions, &FieldPermission{
+				ID:              record.ID(),
+				PermissionSetID: record.StringField("ParentId"),
+				SobjectType:     record.StringField("SobjectType"),
+				Field:           record.StringField("Field"),
+				Read:            read,
+				Edit:            edit,
+			})
+		}
+		if pageToken == "" {
+			return permissions, ratelimitData, nil
+		}
+	}
+}
+
 // GetProfiles - // SELECT Id, Name FROM Profile.
 func (c *SalesforceClient) GetProfiles(
 	ctx context.Context,
@@ -566,7 +866,7 @@ func (c *SalesforceClient) getAssignments(
 	error,
 ) {
 	query := NewQuery(TableNameUsers).WhereEq("UserType", "Standard").WhereEq(conditionKey, conditionValue)
-	records, paginationUrl, ratelimitData, err := c.query(
+	records, paginationUrl, ratelimitData, err := c.queryLarge(
 		ctx,
 		query,
 		pageToken,
@@ -627,7 +927,7 @@ func (c *SalesforceClient) GetPermissionSetAssignments(
 	error,
 ) {
 	query := NewQuery(TableNamePermissionAssignments).WhereEq("PermissionSetId", permissionSetID)
-	records, paginationUrl, ratelimitData, err := c.query(
+	records, paginationUrl, ratelimitData, err := c.queryLarge(
 		ctx,
 		query,
 		pageToken,
@@ -675,7 +975,7 @@ func (c *SalesforceClient) GetGroupMemberships(
 ) {
 	logger := ctxzap.Extract(ctx)
 	query := NewQuery(TableNameGroupMemberships).WhereEq("GroupId", groupID)
-	records, paginationUrl, ratelimitData, err := c.query(
+	records, paginationUrl, ratelimitData, err := c.queryLarge(
 		ctx,
 		query,
 		pageToken,
@@ -1046,6 +1346,220 @@ func (c *SalesforceClient) GetConnectedApplications(
 	return apps, paginationUrl, ratelimitData, nil
 }
 
+// GetConnectedApplicationsByName looks connected apps up by name. Names that
+// don't match an app are left out.
+func (c *SalesforceClient) GetConnectedApplicationsByName(
+	ctx context.Context,
+	names []string,
+) (
+	[]*ConnectedApplication,
+	*v2.RateLimitDescription,
+	error,
+) {
+	if len(names) == 0 {
+		return []*ConnectedApplication{}, nil, nil
+	}
+	query := NewQuery(TableNameConnectedApps).WhereIn("Name", names...)
+	records, _, ratelimitData, err := c.query(
+		ctx,
+		query,
+		"",
+		len(names),
+	)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+
+	apps := make([]*ConnectedApplication, 0, len(records))
+	for _, record := range records {
+		apps = append(apps, &ConnectedApplication{
+			ID:   record.ID(),
+			Name: record.StringField("Name"),
+		})
+	}
+	return apps, ratelimitData, nil
+}
+
+func (c *SalesforceClient) GetConnectedApplicationByID(
+	ctx context.Context,
+	id string,
+) (
+	*ConnectedApplication,
+	*v2.RateLimitDescription,
+	error,
+) {
+	record, ratelimitData, err := c.getSObject(
+		ctx,
+		NewQuery(TableNameConnectedApps).WhereEq("Id", id),
+	)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	return &ConnectedApplication{
+		ID:   record.ID(),
+		Name: record.StringField("Name"),
+	}, ratelimitData, nil
+}
+
+// GetOauthTokens - SELECT Id, AppName, UserId, LastUsedDate, UseCount FROM
+// OauthToken WHERE AppName = appName ORDER BY UserId, Id. Ordering by user
+// keeps each user's tokens together, though they can still span two pages.
+func (c *SalesforceClient) GetOauthTokens(
+	ctx context.Context,
+	appName string,
+	pageToken string,
+	pageSize int,
+) (
+	[]*OauthToken,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNameOauthTokens).
+		WhereEq("AppName", appName).
+		OrderBy("UserId")
+	records, paginationUrl, ratelimitData, err := c.queryLarge(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+
+	tokens, err := oauthTokensFromRecords(records)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+	return tokens, paginationUrl, ratelimitData, nil
+}
+
+func oauthTokensFromRecords(records []simpleforce.SObject) ([]*OauthToken, error) {
+	tokens := make([]*OauthToken, 0, len(records))
+	for _, record := range records {
+		useCount, err := getIntField(record, "UseCount")
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, &OauthToken{
+			ID:           record.ID(),
+			AppName:      record.StringField("AppName"),
+			UserID:       record.StringField("UserId"),
+			LastUsedDate: record.StringField("LastUsedDate"),
+			UseCount:     useCount,
+		})
+	}
+	return tokens, nil
+}
+
+// RevokeOauthTokens deletes every token the user holds for the connected app,
+// which signs the app out of the user's account. The tokens are deleted 200
+// per sObject Collections request. Returns ErrObjectNotFound if
+// the user has no tokens for the app.
+func (c *SalesforceClient) RevokeOauthTokens(
+	ctx context.Context,
+	userID string,
+	appName string,
+) (*v2.RateLimitDescription, error) {
+	records, _, ratelimitData, err := c.query(
+		ctx,
+		NewQuery(TableNameOauthTokens).
+			WhereEq("UserId", userID).
+			WhereEq("AppName", appName),
+		"",
+		-1,
+	)
+	if err != nil {
+		return ratelimitData, err
+	}
+	if len(records) == 0 {
+		return ratelimitData, ErrObjectNotFound
+	}
+
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID())
+	}
+	// Tokens are independent, so each one that can be deleted is, and a token
+	// that expired in the meantime is already gone.
+	for chunk := range slices.Chunk(ids, compositeSObjectsMaxRecords) {
+		var results []*CollectionResult
+		results, ratelimitData, err = c.DeleteObjects(ctx, chunk, false)
+		if results == nil && err != nil {
+			return ratelimitData, fmt.Errorf("baton-salesforce: failed to revoke oauth tokens: %w", err)
+		}
+		for _, result := range results {
+			if err := result.Err(); err != nil && !errors.Is(err, ErrObjectNotFound) {
+				return ratelimitData, fmt.Errorf("baton-salesforce: failed to revoke oauth token %s: %w", result.ID, err)
+			}
+		}
+	}
+	return ratelimitData, nil
+}
+
+// GetConnectedApplicationAccess - SELECT Id, ParentId, SetupEntityId,
+// SetupEntityType FROM SetupEntityAccess WHERE SetupEntityId = appID. Each
+// permission set is then looked up to tell which ones a profile owns.
+func (c *SalesforceClient) GetConnectedApplicationAccess(
+	ctx context.Context,
+	appID string,
+	pageToken string,
+	pageSize int,
+) (
+	[]*SetupEntityAccess,
+	string,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := NewQuery(TableNameSetupEntityAccess).
+		WhereEq("SetupEntityId", appID).
+		WhereEq("SetupEntityType", TableNameConnectedApps)
+	records, paginationUrl, ratelimitData, err := c.query(
+		ctx,
+		query,
+		pageToken,
+		pageSize,
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+
+	accesses := make([]*SetupEntityAccess, 0, len(records))
+	permissionSetIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		permissionSetID := record.StringField("ParentId")
+		accesses = append(accesses, &SetupEntityAccess{
+			ID:              record.ID(),
+			PermissionSetID: permissionSetID,
+			SetupEntityID:   record.StringField("SetupEntityId"),
+		})
+		permissionSetIDs = append(permissionSetIDs, permissionSetID)
+	}
+	if len(permissionSetIDs) == 0 {
+		return accesses, paginationUrl, ratelimitData, nil
+	}
+
+	profileRecords, _, ratelimitData, err := c.query(
+		ctx,
+		NewQuery(TableNamePermissionsSets, "ProfileId").
+			WhereIn(SalesforcePK, permissionSetIDs...),
+		"",
+		len(permissionSetIDs),
+	)
+	if err != nil {
+		return nil, "", ratelimitData, err
+	}
+	profileIDs := make(map[string]string, len(profileRecords))
+	for _, record := range profileRecords {
+		profileIDs[record.ID()] = record.StringField("ProfileId")
+	}
+	for _, access := range accesses {
+		access.ProfileID = profileIDs[access.PermissionSetID]
+	}
+	return accesses, paginationUrl, ratelimitData, nil
+}
+
 // AgentforceAPIVersion is the REST API version used for BotDefinition queries.
 // BotDefinition (Einstein Bots and Agentforce Agents) is GA in API v60.0; the
 // shared client is pinned to an older default, so this query opts into v60.0.
@@ -1072,7 +1586,7 @@ func (c *SalesforceClient) GetBotDefinitions(
 		AgentforceAPIVersion,
 	)
 	if err != nil {
-		if isSObjectNotSupportedError(err) {
+		if errors.Is(err, ErrSObjectNotSupported) {
 			ctxzap.Extract(ctx).Info(
 				"salesforce-client: BotDefinition SObject not available; skipping agent sync (Agentforce/Einstein Bots not enabled)",
 				zap.Error(err),
@@ -1244,6 +1758,77 @@ func (c *SalesforceClient) GetUserLogin(
 	return userLogin, ratelimitData, nil
 }
 
+// SetUserFrozen freezes or unfreezes a user's login through their UserLogin
+// record. Frozen users can't log in, but unlike deactivated users they keep
+// their licenses and ownerships. Returns ErrObjectNotFound if the user has no
+// UserLogin record.
+func (c *SalesforceClient) SetUserFrozen(
+	ctx context.Context,
+	userId string,
+	frozen bool,
+) (
+	*UserLogin,
+	*v2.RateLimitDescription,
+	error,
+) {
+	userLogin, ratelimitData, err := c.GetUserLogin(ctx, userId)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	if userLogin == nil {
+		return nil, ratelimitData, ErrObjectNotFound
+	}
+
+	ratelimitData, err = c.UpdateObject(
+		ctx,
+		TableNameUserLogin,
+		userLogin.ID,
+		map[string]interface{}{"IsFrozen": frozen},
+	)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+
+	userLogin.IsFrozen = frozen
+	return userLogin, ratelimitData, nil
+}
+
+// UnlockUser clears a password lockout by resetting IsPasswordLocked on the
+// user's UserLogin record. Users that aren't locked are left alone. Returns
+// ErrObjectNotFound if the user has no UserLogin record.
+func (c *SalesforceClient) UnlockUser(
+	ctx context.Context,
+	userId string,
+) (
+	*UserLogin,
+	*v2.RateLimitDescription,
+	error,
+) {
+	userLogin, ratelimitData, err := c.GetUserLogin(ctx, userId)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	if userLogin == nil {
+		return nil, ratelimitData, ErrObjectNotFound
+	}
+	if !userLogin.IsPasswordLocked {
+		return userLogin, ratelimitData, nil
+	}
+
+	ratelimitData, err = c.UpdateObject(
+		ctx,
+		TableNameUserLogin,
+		userLogin.ID,
+		map[string]interface{}{"IsPasswordLocked": false},
+	)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+
+	userLogin.IsPasswordLocked = false
+	return userLogin, ratelimitData, nil
+}
+
 func (c *SalesforceClient) GetTerritories(
 	ctx context.Context,
 	pageToken string,
@@ -1347,7 +1932,7 @@ func (c *SalesforceClient) AddUserToTerritory(
 		},
 	)
 	if err != nil {
-		if isSalesforceDuplicateError(err) {
+		if errors.Is(err, ErrObjectAlreadyExists) {
 			return ratelimitData, ErrObjectAlreadyExists
 		}
 		return ratelimitData, err
@@ -1384,7 +1969,7 @@ func (c *SalesforceClient) AddUserToTerritoryWithRole(
 		"RoleInTerritory2": role,
 	})
 	if err != nil {
-		if isSalesforceDuplicateError(err) {
+		if errors.Is(err, ErrObjectAlreadyExists) {
 			return ratelimitData, ErrObjectAlreadyExists
 		}
 		return ratelimitData, err
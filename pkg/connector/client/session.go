@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/conductorone/simpleforce"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+// refreshingTokenSource is oauth2.ReuseTokenSource with a way to throw the
+// cached token away before it expires. Salesforce ends sessions on its own
+// schedule (the org's session timeout, a password change, an admin revoking
+// the app) and usually issues access tokens without an expiry, so only an
+// INVALID_SESSION_ID response tells us a new one is needed.
+type refreshingTokenSource struct {
+	source oauth2.TokenSource
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func newRefreshingTokenSource(token *oauth2.Token, source oauth2.TokenSource) *refreshingTokenSource {
+	return &refreshingTokenSource{
+		source: source,
+		token:  token,
+	}
+}
+
+func (s *refreshingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// invalidate discards the cached token if it is still accessToken, so that
+// requests which failed on the same session only fetch one new token.
+func (s *refreshingTokenSource) invalidate(accessToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && s.token.AccessToken == accessToken {
+		s.token = nil
+	}
+}
+
+// tokenFunc adapts a function to oauth2.TokenSource.
+type tokenFunc func() (*oauth2.Token, error)
+
+func (f tokenFunc) Token() (*oauth2.Token, error) {
+	return f()
+}
+
+// newPasswordTokenSource logs in with a username and password and hands out
+// the resulting session ID as the access token, logging in again whenever
+// the session is invalidated.
+func newPasswordTokenSource(
+	ctx context.Context,
+	loginURL string,
+	username string,
+	password string,
+	securityToken string,
+) (*refreshingTokenSource, error) {
+	logger := ctxzap.Extract(ctx)
+	// Later logins happen on whichever request found the session expired, so
+	// they must not depend on the lifetime of the request that got here first.
+	ctx = context.WithoutCancel(ctx)
+	login := func() (*oauth2.Token, error) {
+		loginClient, err := simpleforce.NewClient(
+			ctx,
+			loginURL,
+			SalesforceClientID,
+			simpleforce.DefaultAPIVersion,
+		)
+		if err != nil {
+			return nil, err
+		}
+		err = loginClient.LoginPassword(ctx, username, password, securityToken)
+		if err != nil {
+			logger.Error("could not login", zap.Error(err))
+			return nil, err
+		}
+		token := &oauth2.Token{
+			AccessToken: loginClient.GetSid(),
+			TokenType:   "Bearer",
+		}
+		return token.WithExtra(map[string]any{"instance_url": loginClient.GetLoc()}), nil
+	}
+
+	token, err := login()
+	if err != nil {
+		return nil, err
+	}
+	return newRefreshingTokenSource(token, tokenFunc(login)), nil
+}
+
+// renewSession handles a 401, which Salesforce returns with
+// INVALID_SESSION_ID once the access token or session has expired. It
+// fetches a new token and sends the request once more, or returns the
+// original response when there is no new token to try or the request body
+// can't be read again.
+func (t *salesforceHttpTransport) renewSession(
+	request *http.Request,
+	staleToken string,
+	response *http.Response,
+) (*http.Response, error) {
+	logger := ctxzap.Extract(request.Context())
+	if request.Body != nil && request.GetBody == nil {
+		return response, nil
+	}
+
+	// Token sources handed to us from outside can't be forced to refresh, but
+	// may have refreshed on their own by now.
+	if refreshing, ok := t.tokenSource.(*refreshingTokenSource); ok {
+		refreshing.invalidate(staleToken)
+	}
+	token, err := t.tokenSource.Token()
+	if err != nil {
+		logger.Warn("baton-salesforce: failed to renew session", zap.Error(err))
+		return response, nil
+	}
+	if token.AccessToken == staleToken {
+		return response, nil
+	}
+
+	replay := request.Clone(request.Context())
+	if request.GetBody != nil {
+		replay.Body, err = request.GetBody()
+		if err != nil {
+			return response, nil
+		}
+	}
+	replay.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+
+	logger.Debug("baton-salesforce: session expired, replaying request with a new session")
+	response.Body.Close()
+	return t.base.RoundTrip(replay)
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func invalidSession(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	_, _ = w.Write([]byte(`[{"message":"Session expired or invalid","errorCode":"INVALID_SESSION_ID"}]`))
+}
+
+func TestRenewSession(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should log in again when the password session expires", func(t *testing.T) {
+		var logins atomic.Int32
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/services/Soap/u/") {
+				session := logins.Add(1)
+				w.Header().Set("Content-Type", "text/xml")
+				_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body><loginResponse><result>
+<serverUrl>%s/services/Soap/u/58.0</serverUrl><sessionId>session-%d</sessionId>
+</result></loginResponse></soapenv:Body></soapenv:Envelope>`, server.URL, session)
+				return
+			}
+			// Only the latest session is valid, and the first one has already
+			// timed out by the time it is used.
+			if logins.Load() < 2 || r.Header.Get("Authorization") != fmt.Sprintf("Bearer session-%d", logins.Load()) {
+				invalidSession(w)
+				return
+			}
+			writeJSON(t, w, map[string]any{"totalSize": 0, "done": true, "records": []any{}})
+		}))
+		t.Cleanup(server.Close)
+
+		c := New(server.URL, nil, "user@example.com", "password", "token")
+		require.NoError(t, c.Initialize(ctx))
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, _, err := c.query(ctx, NewQuery(TableNameUsers), "", PageSizeDefault)
+				require.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, int32(2), logins.Load())
+	})
+
+	t.Run("should refresh the token and replay the request body", func(t *testing.T) {
+		var refreshes atomic.Int32
+		tokenSource := newRefreshingTokenSource(
+			&oauth2.Token{AccessToken: "token-0"},
+			tokenFunc(func() (*oauth2.Token, error) {
+				return &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", refreshes.Add(1))}, nil
+			}),
+		)
+
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(body))
+			if r.Header.Get("Authorization") != "Bearer token-1" {
+				invalidSession(w)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(server.Close)
+
+		c := New(server.URL, tokenSource, "", "", "")
+		require.NoError(t, c.Initialize(ctx))
+
+		_, err := c.UpdateObject(ctx, TableNameUsers, "005A", map[string]interface{}{"IsActive": false})
+		require.NoError(t, err)
+		require.Equal(t, int32(1), refreshes.Load())
+		require.Len(t, bodies, 2)
+		require.Equal(t, bodies[0], bodies[1])
+	})
+
+	t.Run("should fail when no new token is available", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(ctx, t, func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			invalidSession(w)
+		})
+
+		_, _, _, err := c.query(ctx, NewQuery(TableNameUsers), "", PageSizeDefault)
+		require.ErrorIs(t, err, ErrInvalidSession)
+		require.Equal(t, 1, attempts)
+	})
+}
@@ -1,7 +1,9 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/mail"
@@ -171,6 +173,8 @@ func (c *SalesforceClient) GetUserByEmail(
 			FirstName:     record.StringField("FirstName"),
 			LastName:      record.StringField("LastName"),
 			UserType:      record.StringField("UserType"),
+			ProfileID:     record.StringField("ProfileId"),
+			UserRoleID:    record.StringField("UserRoleId"),
 			IsActive:      isActive,
 			LastLoginDate: lastLogin,
 		})
@@ -189,9 +193,27 @@ func (c *SalesforceClient) SendResetPasswordEmail(ctx context.Context, userId st
 	resetPath := fmt.Sprintf(ResetPasswordPath, userId)
 
 	_, err := c.client.ApexREST(ctx, http.MethodDelete, resetPath, nil)
+	if err != nil {
+		return classifyError(err)
+	}
+
+	return nil
+}
+
+// SetPassword sets the password of the user with the given ID. Salesforce
+// rejects passwords that don't meet the org's password policy.
+// https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_sobject_user_password.htm
+func (c *SalesforceClient) SetPassword(ctx context.Context, userId string, password string) error {
+	body, err := json.Marshal(map[string]string{"NewPassword": password})
 	if err != nil {
 		return err
 	}
 
+	passwordPath := fmt.Sprintf(ResetPasswordPath, userId)
+	_, err = c.client.ApexREST(ctx, http.MethodPost, passwordPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("baton-salesforce: failed to set password: %w", classifyError(err))
+	}
+
 	return nil
 }
@@ -7,7 +7,9 @@ func parseSalesforceDatetime(date string) (*time.Time, error) {
 		return nil, nil
 	}
 
-	t, err := time.Parse("2006-01-02T15:04:05.000-0700", date)
+	// REST responses use a numeric offset ("+0000") while Bulk API CSV
+	// results use "Z"; the Z0700 layout accepts both.
+	t, err := time.Parse("2006-01-02T15:04:05.000Z0700", date)
 	if err != nil {
 		return nil, err
 	}
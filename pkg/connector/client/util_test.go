@@ -25,6 +25,11 @@ func TestParseSalesforceDatetime(t *testing.T) {
 			input:    "2025-03-26T16:43:31.000+0000",
 			expected: &validDate,
 		},
+		{
+			name:     "valid bulk date",
+			input:    "2025-03-26T16:43:31.000Z",
+			expected: &validDate,
+		},
 	}
 
 	for _, c := range cases {
@@ -2,16 +2,42 @@ package connector
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/conductorone/baton-salesforce/pkg/connector/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
 )
 
+const (
+	connectedApplicationAccessEntitlementName = "access"
+	setupEntityAccessPageTokenPrefix          = "sea:"
+)
+
+// connectedApplicationBuilder syncs connected apps along with the users that
+// have authorized them. Users authorize an app themselves through OAuth, so
+// access can only be revoked here, by deleting the user's tokens. Apps that
+// are admin approved are also granted to the permission sets and profiles
+// allowed to use them, which expand to whoever is assigned those.
 type connectedApplicationBuilder struct {
 	client *client.SalesforceClient
 }
 
+// oauthTokensCursor pages through the app's tokens. The app name is looked up
+// once and carried along, as is the usage of a user whose tokens run past the
+// end of the page.
+type oauthTokensCursor struct {
+	AppName string             `json:"app_name"`
+	Token   string             `json:"token,omitempty"`
+	Pending *client.OauthToken `json:"pending,omitempty"`
+}
+
 func (o *connectedApplicationBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
 	return resourceTypeConnectedApplication
 }
@@ -75,7 +101,24 @@ func (o *connectedApplicationBuilder) Entitlements(
 	*rs.SyncOpResults,
 	error,
 ) {
-	return nil, nil, nil
+	entitlements := []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			connectedApplicationAccessEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeUser, resourceTypePermissionSet, resourceTypeProfile),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s Connected App Access", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf(
+					"Can use the %s connected app in Salesforce. Revoking it from a user deletes their OAuth tokens for the app",
+					resource.DisplayName,
+				),
+			),
+		),
+	}
+
+	return entitlements, nil, nil
 }
 
 func (o *connectedApplicationBuilder) Grants(
@@ -87,7 +130,220 @@ func (o *connectedApplicationBuilder) Grants(
 	*rs.SyncOpResults,
 	error,
 ) {
-	return nil, nil, nil
+	token := &attrs.PageToken
+	if strings.HasPrefix(token.Token, setupEntityAccessPageTokenPrefix) {
+		// Phase 2: permission sets and profiles allowed to use the app
+		return o.setupEntityAccessGrants(
+			ctx,
+			resource,
+			strings.TrimPrefix(token.Token, setupEntityAccessPageTokenPrefix),
+			token.Size,
+		)
+	}
+
+	// Phase 1: users that have authorized the app
+	cursor := &oauthTokensCursor{}
+	if token.Token != "" {
+		if err := json.Unmarshal([]byte(token.Token), cursor); err != nil {
+			return nil, nil, fmt.Errorf("salesforce-connector: invalid oauth token page token: %w", err)
+		}
+	}
+	if cursor.AppName == "" {
+		appName, ratelimitData, err := o.appName(ctx, resource)
+		if err != nil {
+			return nil, &rs.SyncOpResults{Annotations: client.WithRateLimitAnnotations(ratelimitData)}, err
+		}
+		cursor.AppName = appName
+	}
+
+	tokens, nextTokensToken, ratelimitData, err := o.client.GetOauthTokens(
+		ctx,
+		cursor.AppName,
+		cursor.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	// A user gets a token per device or session, so fold them into one grant.
+	byUser := make(map[string]*client.OauthToken)
+	userIDs := make([]string, 0)
+	if cursor.Pending != nil {
+		byUser[cursor.Pending.UserID] = cursor.Pending
+		userIDs = append(userIDs, cursor.Pending.UserID)
+	}
+	for _, oauthToken := range tokens {
+		existing, ok := byUser[oauthToken.UserID]
+		if !ok {
+			usage := *oauthToken
+			byUser[oauthToken.UserID] = &usage
+			userIDs = append(userIDs, oauthToken.UserID)
+			continue
+		}
+		existing.UseCount += oauthToken.UseCount
+		if oauthToken.LastUsedDate > existing.LastUsedDate {
+			existing.LastUsedDate = oauthToken.LastUsedDate
+		}
+	}
+
+	// The last user's tokens may carry on into the next page, so hold their
+	// usage back until it's complete.
+	var pending *client.OauthToken
+	if nextTokensToken != "" && len(userIDs) > 0 {
+		pending = byUser[userIDs[len(userIDs)-1]]
+		userIDs = userIDs[:len(userIDs)-1]
+	}
+
+	grants := make([]*v2.Grant, 0, len(userIDs))
+	for _, userID := range userIDs {
+		usage := byUser[userID]
+		grants = append(grants, grant.NewGrant(
+			resource,
+			connectedApplicationAccessEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceTypeUser.Id,
+				Resource:     userID,
+			},
+			grant.WithGrantMetadata(map[string]interface{}{
+				"last_used_date": usage.LastUsedDate,
+				"use_count":      usage.UseCount,
+			}),
+		))
+	}
+
+	nextToken := setupEntityAccessPageTokenPrefix
+	if nextTokensToken != "" {
+		nextCursor, err := json.Marshal(oauthTokensCursor{
+			AppName: cursor.AppName,
+			Token:   nextTokensToken,
+			Pending: pending,
+		})
+		if err != nil {
+			return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+		}
+		nextToken = string(nextCursor)
+	}
+	return grants, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+// setupEntityAccessGrants grants the app to each permission set allowed to use
+// it, or to the owning profile for a profile's permission set. Each grant
+// expands to whoever is assigned the permission set or profile.
+func (o *connectedApplicationBuilder) setupEntityAccessGrants(
+	ctx context.Context,
+	resource *v2.Resource,
+	pageToken string,
+	pageSize int,
+) (
+	[]*v2.Grant,
+	*rs.SyncOpResults,
+	error,
+) {
+	accesses, nextAccessToken, ratelimitData, err := o.client.GetConnectedApplicationAccess(
+		ctx,
+		resource.Id.Resource,
+		pageToken,
+		pageSize,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	grants := make([]*v2.Grant, 0, len(accesses))
+	for _, access := range accesses {
+		principal := &v2.ResourceId{
+			ResourceType: resourceTypePermissionSet.Id,
+			Resource:     access.PermissionSetID,
+		}
+		assignedEntitlementName := permissionSetAssignmentEntitlementName
+		if access.ProfileID != "" {
+			principal = &v2.ResourceId{
+				ResourceType: resourceTypeProfile.Id,
+				Resource:     access.ProfileID,
+			}
+			assignedEntitlementName = profileAssignmentEntitlementName
+		}
+		assignedEntitlementID := fmt.Sprintf("%s:%s:%s",
+			principal.ResourceType,
+			principal.Resource,
+			assignedEntitlementName,
+		)
+		grants = append(grants, grant.NewGrant(
+			resource,
+			connectedApplicationAccessEntitlementName,
+			principal,
+			grant.WithAnnotation(&v2.GrantExpandable{
+				EntitlementIds: []string{assignedEntitlementID},
+			}),
+			grant.WithAnnotation(&v2.GrantImmutable{}),
+		))
+	}
+
+	var nextToken string
+	if nextAccessToken != "" {
+		nextToken = setupEntityAccessPageTokenPrefix + nextAccessToken
+	}
+	return grants, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func (o *connectedApplicationBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+) (annotations.Annotations, error) {
+	return nil, fmt.Errorf("salesforce-connector: connected app access is granted by the user authorizing the app")
+}
+
+func (o *connectedApplicationBuilder) Revoke(
+	ctx context.Context,
+	grant *v2.Grant,
+) (annotations.Annotations, error) {
+	if grant.Principal.Id.ResourceType != resourceTypeUser.Id {
+		return nil, fmt.Errorf("salesforce-connector: only users can have connected app access revoked, change the permission set or profile instead")
+	}
+
+	appName, ratelimitData, err := o.appName(ctx, grant.Entitlement.Resource)
+	if err != nil {
+		return client.WithRateLimitAnnotations(ratelimitData), err
+	}
+
+	ratelimitData, err = o.client.RevokeOauthTokens(
+		ctx,
+		grant.Principal.Id.Resource,
+		appName,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		if errors.Is(err, client.ErrObjectNotFound) {
+			outputAnnotations.Append(&v2.GrantAlreadyRevoked{})
+			return outputAnnotations, nil
+		}
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
+}
+
+// appName looks the app's name up by ID. OauthToken only knows the app by
+// name, so tokens are matched against the current name rather than the
+// display name carried on the resource, which may be stale.
+func (o *connectedApplicationBuilder) appName(
+	ctx context.Context,
+	resource *v2.Resource,
+) (string, *v2.RateLimitDescription, error) {
+	app, ratelimitData, err := o.client.GetConnectedApplicationByID(ctx, resource.Id.Resource)
+	if err != nil {
+		return "", ratelimitData, err
+	}
+	return app.Name, ratelimitData, nil
 }
 
 func newConnectedApplicationBuilder(client *client.SalesforceClient) *connectedApplicationBuilder {
@@ -0,0 +1,213 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	"github.com/conductorone/baton-salesforce/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectedApplications(t *testing.T) {
+	ctx := context.Background()
+
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newConnectedApplicationBuilder(salesforceClient)
+
+	app, _ := connectedApplicationResource(ctx, &client.ConnectedApplication{
+		ID:   "0H41X",
+		Name: "Salesforce for iOS",
+	})
+
+	t.Run("should grant one access per authorizing user", func(t *testing.T) {
+		grants, results, err := c.Grants(ctx, app, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.NotNil(t, results)
+		test.AssertNoRatelimitAnnotations(t, results.Annotations)
+		require.Equal(t, setupEntityAccessPageTokenPrefix, results.NextPageToken)
+
+		require.Len(t, grants, 2)
+		require.Equal(t, "0051X", grants[0].Principal.Id.Resource)
+		require.Equal(t, "connected_application:0H41X:access", grants[0].Entitlement.Id)
+
+		var metadata v2.GrantMetadata
+		found, err := test.UnmarshalFromAnys(&metadata, grants[0].Annotations)
+		require.Nil(t, err)
+		require.True(t, found)
+		fields := metadata.GetMetadata().GetFields()
+		require.Equal(t, float64(15), fields["use_count"].GetNumberValue())
+		require.Equal(t, "2025-04-01T00:00:00.000+0000", fields["last_used_date"].GetStringValue())
+
+		require.Equal(t, "0052X", grants[1].Principal.Id.Resource)
+	})
+
+	t.Run("should grant access through permission sets and profiles", func(t *testing.T) {
+		grants, results, err := c.Grants(ctx, app, rs.SyncOpAttrs{
+			PageToken: pagination.Token{Size: 100, Token: setupEntityAccessPageTokenPrefix},
+		})
+		require.Nil(t, err)
+		require.NotNil(t, results)
+		require.Empty(t, results.NextPageToken)
+
+		require.Len(t, grants, 2)
+		expectedExpansions := map[string]string{
+			"permission:PS2X": "permission:PS2X:assigned",
+			"profile:198X":    "profile:198X:assigned",
+		}
+		for _, g := range grants {
+			principal := g.Principal.Id.ResourceType + ":" + g.Principal.Id.Resource
+			expected, ok := expectedExpansions[principal]
+			require.True(t, ok, "unexpected principal %s", principal)
+
+			var expandable v2.GrantExpandable
+			found, err := test.UnmarshalFromAnys(&expandable, g.Annotations)
+			require.Nil(t, err)
+			require.True(t, found)
+			require.Equal(t, []string{expected}, expandable.EntitlementIds)
+		}
+	})
+
+	t.Run("should revoke every token the user holds", func(t *testing.T) {
+		user, _ := userResource(ctx, &client.SalesforceUser{ID: "0051X"}, nil, false)
+		ent := v2.Entitlement{
+			Id:       entitlement.NewEntitlementID(app, connectedApplicationAccessEntitlementName),
+			Resource: app,
+		}
+
+		revokeAnnotations, err := c.Revoke(ctx, &v2.Grant{Entitlement: &ent, Principal: user})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, revokeAnnotations)
+
+		if err := uhttp.ClearCaches(ctx); err != nil {
+			t.Fatal(err)
+		}
+		grants, _, err := c.Grants(ctx, app, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Len(t, grants, 1)
+		require.Equal(t, "0052X", grants[0].Principal.Id.Resource)
+
+		revokeAnnotations, err = c.Revoke(ctx, &v2.Grant{Entitlement: &ent, Principal: user})
+		require.Nil(t, err)
+		var alreadyRevoked v2.GrantAlreadyRevoked
+		found, err := test.UnmarshalFromAnys(&alreadyRevoked, revokeAnnotations)
+		require.Nil(t, err)
+		require.True(t, found)
+	})
+}
+
+// onePerPage serves the fixture server's query results one record per page,
+// so that a user's tokens end up split across pages.
+func onePerPage(server *httptest.Server) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "sobjects") {
+			server.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := httptest.NewRecorder()
+		server.Config.Handler.ServeHTTP(recorder, r)
+		var result test.QueryResult
+		if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		queryString := r.URL.Query().Get("q")
+		offset := 0
+		if queryString == "" {
+			queryString = r.URL.Query().Get("next")
+			offset, _ = strconv.Atoi(r.URL.Query().Get("offset"))
+		}
+		result.Records = result.Records[offset:min(offset+1, len(result.Records))]
+		result.Done = offset+1 >= result.TotalSize
+		result.NextRecordsURL = ""
+		if !result.Done {
+			result.NextRecordsURL = fmt.Sprintf(
+				"/services/data?next=%s&total=%d&offset=%d",
+				url.QueryEscape(queryString),
+				result.TotalSize,
+				offset+1,
+			)
+		}
+
+		for key, values := range recorder.Header() {
+			w.Header()[key] = values
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+}
+
+func TestConnectedApplicationTokensAcrossPages(t *testing.T) {
+	ctx := context.Background()
+
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	pagedServer := onePerPage(server)
+	defer pagedServer.Close()
+
+	salesforceClient, err := test.Client(ctx, pagedServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newConnectedApplicationBuilder(salesforceClient)
+
+	// The display name is stale, tokens are matched on the app's current name.
+	app, _ := connectedApplicationResource(ctx, &client.ConnectedApplication{
+		ID:   "0H41X",
+		Name: "Old name",
+	})
+
+	// 0051X has two tokens, which land on separate pages.
+	grants := make([]*v2.Grant, 0)
+	pageToken := ""
+	for {
+		page, results, err := c.Grants(ctx, app, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 1, Token: pageToken}})
+		require.Nil(t, err)
+		grants = append(grants, page...)
+		pageToken = results.NextPageToken
+		if pageToken == setupEntityAccessPageTokenPrefix {
+			break
+		}
+		require.NotEmpty(t, pageToken)
+	}
+
+	require.Len(t, grants, 2)
+	require.Equal(t, "0051X", grants[0].Principal.Id.Resource)
+	require.Equal(t, "0052X", grants[1].Principal.Id.Resource)
+
+	var metadata v2.GrantMetadata
+	found, err := test.UnmarshalFromAnys(&metadata, grants[0].Annotations)
+	require.Nil(t, err)
+	require.True(t, found)
+	fields := metadata.GetMetadata().GetFields()
+	require.Equal(t, float64(15), fields["use_count"].GetNumberValue())
+	require.Equal(t, "2025-04-01T00:00:00.000+0000", fields["last_used_date"].GetStringValue())
+}
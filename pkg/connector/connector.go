@@ -35,6 +35,11 @@ type Salesforce struct {
 	syncConnectedApps            bool
 	syncDeactivatedUsers         bool
 	syncNonStandardUsers         bool
+	syncQueues                   bool
+	roleHierarchyAccess          bool
+	syncObjectPermissions        bool
+	highRiskSystemPermissions    []string
+	fieldPermissions             []string
 	licenseToLeastProfileMapping map[string]string
 }
 
@@ -60,19 +65,32 @@ func fallBackToHTTPS(domain string) (string, error) {
 func (d *Salesforce) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncerV2 {
 	rv := []connectorbuilder.ResourceSyncerV2{
 		newUserBuilder(d.client, d.shouldUseUsernameForEmail, d.syncDeactivatedUsers, d.syncNonStandardUsers),
-		newGroupBuilder(d.client),
-		newPermissionBuilder(d.client),
+		newGroupBuilder(d.client, d.syncQueues),
+		newPermissionBuilder(
+			d.client,
+			d.syncObjectPermissions,
+			d.highRiskSystemPermissions,
+			d.fieldPermissions,
+		),
 		newProfileBuilder(d.client, d.licenseToLeastProfileMapping),
-		newRoleBuilder(d.client),
+		newRoleBuilder(d.client, d.roleHierarchyAccess),
 		newPermissionSetGroupBuilder(d.client),
 		newTerritoryBuilder(d.client),
-		// The agent resource type is gated by the OptInRequired annotation, so
-		// it is registered unconditionally and only synced when opted into.
+		// The agent and license resource types are gated by the OptInRequired
+		// annotation, so they are registered unconditionally and only synced
+		// when opted into.
 		newAgentBuilder(d.client),
+		newPermissionSetLicenseBuilder(d.client),
+		newFeatureLicenseBuilder(d.client),
+		newUserLicenseBuilder(d.client),
+		newPackageLicenseBuilder(d.client),
 	}
 	if d.syncConnectedApps {
 		rv = append(rv, newConnectedApplicationBuilder(d.client))
 	}
+	if d.syncQueues {
+		rv = append(rv, newQueueBuilder(d.client))
+	}
 	return rv
 }
 
@@ -201,6 +219,127 @@ var updateUserStatusActionSchema = &v2.BatonActionSchema{
 	},
 }
 
+// newFreezeUserActionSchema describes freeze_user and unfreeze_user, which
+// only differ in the state they set.
+func newFreezeUserActionSchema(name string, description string) *v2.BatonActionSchema {
+	return &v2.BatonActionSchema{
+		Name: name,
+		Arguments: []*configpb.Field{
+			{
+				Name:        "resource_id",
+				DisplayName: "User Resource ID",
+				Description: description,
+				Field:       &configpb.Field_StringField{},
+				IsRequired:  true,
+			},
+		},
+		ReturnTypes: []*configpb.Field{
+			{
+				Name:        "success",
+				DisplayName: "Success",
+				Description: "Whether the user's login was updated successfully",
+				Field:       &configpb.Field_BoolField{},
+			},
+			{
+				Name:        "is_frozen",
+				DisplayName: "Is Frozen",
+				Description: "Whether the user's login is now frozen",
+				Field:       &configpb.Field_BoolField{},
+			},
+		},
+	}
+}
+
+var (
+	freezeUserActionSchema   = newFreezeUserActionSchema("freeze_user", "The ID of the user resource to freeze")
+	unfreezeUserActionSchema = newFreezeUserActionSchema("unfreeze_user", "The ID of the user resource to unfreeze")
+)
+
+var unlockUserActionSchema = &v2.BatonActionSchema{
+	Name: "unlock_user",
+	Arguments: []*configpb.Field{
+		{
+			Name:        "resource_id",
+			DisplayName: "User Resource ID",
+			Description: "The ID of the user resource to unlock",
+			Field:       &configpb.Field_StringField{},
+			IsRequired:  true,
+		},
+	},
+	ReturnTypes: []*configpb.Field{
+		{
+			Name:        "success",
+			DisplayName: "Success",
+			Description: "Whether the user's login was unlocked successfully",
+			Field:       &configpb.Field_BoolField{},
+		},
+		{
+			Name:        "is_password_locked",
+			DisplayName: "Is Password Locked",
+			Description: "Whether the user's login is still locked out",
+			Field:       &configpb.Field_BoolField{},
+		},
+	},
+}
+
+var deprovisionUserActionSchema = &v2.BatonActionSchema{
+	Name: "deprovision_user",
+	Arguments: []*configpb.Field{
+		{
+			Name:        "resource_id",
+			DisplayName: "User Resource ID",
+			Description: "The ID of the user resource to deprovision",
+			Field:       &configpb.Field_StringField{},
+			IsRequired:  true,
+		},
+		{
+			Name:        "successor_id",
+			DisplayName: "Successor User Resource ID",
+			Description: "The ID of the user resource that takes over the user's records",
+			Field:       &configpb.Field_StringField{},
+			IsRequired:  true,
+		},
+		{
+			Name:        "objects",
+			DisplayName: "Objects",
+			Description: "The API names of the Salesforce objects whose records are transferred to the successor. Defaults to Account, Opportunity, Case and Lead",
+			Field:       &configpb.Field_StringSliceField{},
+		},
+	},
+	ReturnTypes: []*configpb.Field{
+		{
+			Name:        "success",
+			DisplayName: "Success",
+			Description: "Whether the user was deprovisioned successfully",
+			Field:       &configpb.Field_BoolField{},
+		},
+		{
+			Name:        "transferred",
+			DisplayName: "Transferred",
+			Description: "How many records of each object were transferred to the successor, keyed by object name",
+			Field:       &configpb.Field_StringMapField{},
+		},
+		{
+			Name:        "transferred_records",
+			DisplayName: "Transferred Records",
+			Description: "How many records were transferred to the successor in total",
+			Field:       &configpb.Field_IntField{},
+		},
+		{
+			Name:        "removed_group_memberships",
+			DisplayName: "Removed Group Memberships",
+			Description: "How many public group and queue memberships were removed",
+			Field:       &configpb.Field_IntField{},
+		},
+		{
+			Name:        "removed_territory_memberships",
+			DisplayName: "Removed Territory Memberships",
+			Description: "How many territory memberships were removed",
+			Field:       &configpb.Field_IntField{},
+		},
+	},
+}
+
 func (d *Salesforce) GlobalActions(ctx context.Context, registry actions.ActionRegistry) error {
 	l := ctxzap.Extract(ctx)
 
@@ -210,6 +349,30 @@ func (d *Salesforce) GlobalActions(ctx context.Context, registry actions.ActionR
 		return err
 	}
 
+	err = registry.Register(ctx, freezeUserActionSchema, d.freezeUser)
+	if err != nil {
+		l.Error("failed to register action", zap.Error(err))
+		return err
+	}
+
+	err = registry.Register(ctx, unfreezeUserActionSchema, d.unfreezeUser)
+	if err != nil {
+		l.Error("failed to register action", zap.Error(err))
+		return err
+	}
+
+	err = registry.Register(ctx, unlockUserActionSchema, d.unlockUser)
+	if err != nil {
+		l.Error("failed to register action", zap.Error(err))
+		return err
+	}
+
+	err = registry.Register(ctx, deprovisionUserActionSchema, d.deprovisionUser)
+	if err != nil {
+		l.Error("failed to register action", zap.Error(err))
+		return err
+	}
+
 	return nil
 }
 
@@ -244,12 +407,20 @@ func New(ctx context.Context, cfg *config.Salesforce, opts *cli.ConnectorOpts) (
 		zap.Bool("securityToken?", cfg.SecurityToken != ""),
 		zap.Bool("clientID?", cfg.SalesforceClientId != ""),
 		zap.String("jwtSubject", cfg.SalesforceJwtSubject),
+		zap.Bool("refreshToken?", cfg.SalesforceRefreshToken != ""),
 		zap.String("loginURL", cfg.SalesforceLoginUrl),
 		zap.Bool("useUsernameForEmail", cfg.UserUsernameForEmail),
 		zap.Bool("syncConnectedApps", cfg.SyncConnectedApps),
 		zap.Bool("syncDeactivatedUsers", cfg.SyncDeactivatedUsers),
 		zap.Bool("syncNonStandardUsers", cfg.SyncNonStandardUsers),
+		zap.Bool("syncQueues", cfg.SyncQueues),
+		zap.Bool("roleHierarchyAccess", cfg.RoleHierarchyAccess),
+		zap.Bool("syncObjectPermissions", cfg.SyncObjectPermissions),
+		zap.Strings("highRiskSystemPermissions", cfg.HighRiskSystemPermissions),
+		zap.Strings("fieldPermissions", cfg.FieldPermissions),
 		zap.Any("licenseToLeastProfileMapping", cfg.GetLicenseToLeastPrivilegedProfileMapping()),
+		zap.Int("bulkQueryThreshold", cfg.BulkQueryThreshold),
+		zap.Int("apiBudgetPercent", cfg.ApiBudgetPercent),
 	)
 
 	var salesforceClient *client.SalesforceClient
@@ -279,6 +450,12 @@ func New(ctx context.Context, cfg *config.Salesforce, opts *cli.ConnectorOpts) (
 			return nil, nil, fmt.Errorf("baton-salesforce: failed to create client credentials token source: %w", err)
 		}
 		salesforceClient = client.New(instanceURL, tokenSource, "", "", "")
+	case config.SalesforceRefreshTokenGroup:
+		tokenSource, err = client.NewRefreshTokenTokenSource(ctx, cfg.SalesforceClientId, cfg.SalesforceClientSecret, cfg.SalesforceRefreshToken, instanceURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-salesforce: failed to create refresh token source: %w", err)
+		}
+		salesforceClient = client.New(instanceURL, tokenSource, "", "", "")
 	case config.SalesforceUsernamePasswordGroup:
 		fallthrough
 	default:
@@ -291,6 +468,21 @@ func New(ctx context.Context, cfg *config.Salesforce, opts *cli.ConnectorOpts) (
 		)
 	}
 
+	salesforceClient.SetBulkQueryThreshold(cfg.BulkQueryThreshold)
+	if cfg.ApiBudgetPercent < 0 || cfg.ApiBudgetPercent > 100 {
+		return nil, nil, fmt.Errorf("baton-salesforce: api-budget-percent must be between 0 and 100, got %d", cfg.ApiBudgetPercent)
+	}
+	salesforceClient.SetAPIBudget(cfg.ApiBudgetPercent)
+
+	highRiskSystemPermissions, err := parseSystemPermissions(cfg.HighRiskSystemPermissions)
+	if err != nil {
+		return nil, nil, err
+	}
+	fieldPermissions, err := parseFieldNames(cfg.FieldPermissions)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	salesforce := Salesforce{
 		client:                       salesforceClient,
 		ctx:                          ctx,
@@ -299,6 +491,11 @@ func New(ctx context.Context, cfg *config.Salesforce, opts *cli.ConnectorOpts) (
 		syncConnectedApps:            cfg.SyncConnectedApps,
 		syncDeactivatedUsers:         cfg.SyncDeactivatedUsers,
 		syncNonStandardUsers:         cfg.SyncNonStandardUsers,
+		syncQueues:                   cfg.SyncQueues,
+		roleHierarchyAccess:          cfg.RoleHierarchyAccess,
+		syncObjectPermissions:        cfg.SyncObjectPermissions,
+		highRiskSystemPermissions:    highRiskSystemPermissions,
+		fieldPermissions:             fieldPermissions,
 		licenseToLeastProfileMapping: cfg.GetLicenseToLeastPrivilegedProfileMapping(),
 	}
 	return &salesforce, nil, nil
@@ -0,0 +1,86 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	"github.com/conductorone/baton-salesforce/test"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParallelSync syncs every resource type at once on a single client, the
+// way the baton-sdk parallel syncer does. Run it with -race.
+func TestParallelSync(t *testing.T) {
+	ctx := context.Background()
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	// Left uninitialized so that the builders race to initialize it.
+	salesforceClient := client.New(server.URL, test.MockTokenSource(), "", "", "")
+	c := &Salesforce{
+		client:                salesforceClient,
+		syncConnectedApps:     true,
+		syncDeactivatedUsers:  true,
+		syncQueues:            true,
+		roleHierarchyAccess:   true,
+		syncObjectPermissions: true,
+	}
+
+	t.Run("should sync all resource types in parallel", func(t *testing.T) {
+		for _, syncer := range c.ResourceSyncers(ctx) {
+			resourceTypeID := syncer.ResourceType(ctx).Id
+			t.Run(resourceTypeID, func(t *testing.T) {
+				t.Parallel()
+				syncResourceType(ctx, t, syncer)
+			})
+		}
+	})
+}
+
+func syncResourceType(
+	ctx context.Context,
+	t *testing.T,
+	syncer connectorbuilder.ResourceSyncerV2,
+) {
+	pToken := pagination.Token{Size: 50}
+	for {
+		resources, results, err := syncer.List(ctx, nil, rs.SyncOpAttrs{PageToken: pToken})
+		require.NoError(t, err)
+		test.AssertNoRatelimitAnnotations(t, results.Annotations)
+
+		for _, resource := range resources {
+			entitlementsToken := pagination.Token{Size: 50}
+			for {
+				_, results, err := syncer.Entitlements(ctx, resource, rs.SyncOpAttrs{PageToken: entitlementsToken})
+				require.NoError(t, err)
+				if results == nil || results.NextPageToken == "" {
+					break
+				}
+				entitlementsToken.Token = results.NextPageToken
+			}
+
+			grantsToken := pagination.Token{Size: 50}
+			for {
+				_, results, err := syncer.Grants(ctx, resource, rs.SyncOpAttrs{PageToken: grantsToken})
+				require.NoError(t, err)
+				if results == nil || results.NextPageToken == "" {
+					break
+				}
+				grantsToken.Token = results.NextPageToken
+			}
+		}
+
+		if results.NextPageToken == "" {
+			return
+		}
+		pToken.Token = results.NextPageToken
+	}
+}
@@ -0,0 +1,106 @@
+package connector
+
+import (
+	"fmt"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+)
+
+// derivedPermissionKind is one kind of access a permission set gives through
+// its own configuration: object access, system permissions or field access.
+type derivedPermissionKind struct {
+	// prefix starts the slug of every entitlement of this kind, e.g. "object:".
+	prefix string
+}
+
+var (
+	objectPermissionKind = derivedPermissionKind{prefix: "object:"}
+	systemPermissionKind = derivedPermissionKind{prefix: "system:"}
+	fieldPermissionKind  = derivedPermissionKind{prefix: "field:"}
+
+	derivedPermissionKinds = []derivedPermissionKind{
+		objectPermissionKind,
+		systemPermissionKind,
+		fieldPermissionKind,
+	}
+)
+
+// describes reports whether the entitlement is of this kind rather than the
+// permission set assignment itself.
+func (k derivedPermissionKind) describes(entitlement *v2.Entitlement) bool {
+	return strings.Contains(entitlement.Id, ":"+k.prefix)
+}
+
+// derivedPermission is one piece of access a permission set's configuration
+// gives, such as Delete on Opportunity or the ModifyAllData permission.
+type derivedPermission struct {
+	kind derivedPermissionKind
+	// name identifies the access within its kind, e.g. "Opportunity:delete".
+	name        string
+	displayName string
+	description string
+}
+
+// entitlementName returns the entitlement slug, e.g. "object:Opportunity:delete".
+func (p derivedPermission) entitlementName() string {
+	return p.kind.prefix + p.name
+}
+
+// isDerivedPermissionEntitlement reports whether the entitlement mirrors part
+// of the permission set's configuration rather than its assignment.
+func isDerivedPermissionEntitlement(entitlement *v2.Entitlement) bool {
+	for _, kind := range derivedPermissionKinds {
+		if kind.describes(entitlement) {
+			return true
+		}
+	}
+	return false
+}
+
+// derivedPermissionEntitlements returns a read-only entitlement for each
+// derived permission of the permission set.
+func derivedPermissionEntitlements(resource *v2.Resource, permissions []derivedPermission) []*v2.Entitlement {
+	entitlements := make([]*v2.Entitlement, 0, len(permissions))
+	for _, permission := range permissions {
+		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(
+			resource,
+			permission.entitlementName(),
+			entitlement.WithGrantableTo(resourceTypePermissionSet),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s: %s", resource.DisplayName, permission.displayName),
+			),
+			entitlement.WithDescription(permission.description),
+			entitlement.WithAnnotation(&v2.EntitlementImmutable{}),
+		))
+	}
+	return entitlements
+}
+
+// derivedPermissionGrants grants the permission set its derived permissions.
+// They follow from how the permission set is configured, so they are granted
+// to the permission set itself and can't be granted or revoked on their own.
+// Each grant expands to whoever is assigned the permission set, directly or
+// through a permission set group, so the access shows up on users.
+func derivedPermissionGrants(resource *v2.Resource, permissions []derivedPermission) []*v2.Grant {
+	assignedEntitlementID := fmt.Sprintf("%s:%s:%s",
+		resourceTypePermissionSet.Id,
+		resource.Id.Resource,
+		permissionSetAssignmentEntitlementName,
+	)
+	grants := make([]*v2.Grant, 0, len(permissions))
+	for _, permission := range permissions {
+		grants = append(grants, grant.NewGrant(
+			resource,
+			permission.entitlementName(),
+			resource.Id,
+			grant.WithAnnotation(&v2.GrantExpandable{
+				EntitlementIds: []string{assignedEntitlementID},
+			}),
+			grant.WithAnnotation(&v2.GrantImmutable{}),
+		))
+	}
+	return grants
+}
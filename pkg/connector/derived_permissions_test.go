@@ -0,0 +1,111 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	"github.com/conductorone/baton-salesforce/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerivedPermissions(t *testing.T) {
+	permission, err := permissionResource(&client.SalesforcePermission{ID: "345X", Type: "type", Name: "name"}, nil)
+	require.NoError(t, err)
+	derived := []derivedPermission{
+		{
+			kind:        objectPermissionKind,
+			name:        "Opportunity:delete",
+			displayName: "Delete Opportunity",
+			description: "Grants Delete on Opportunity records",
+		},
+		{
+			kind:        systemPermissionKind,
+			name:        "ModifyAllData",
+			displayName: "ModifyAllData",
+			description: "High-risk system permission ModifyAllData",
+		},
+	}
+
+	t.Run("should add a read-only entitlement per derived permission", func(t *testing.T) {
+		entitlements := derivedPermissionEntitlements(permission, derived)
+		require.Len(t, entitlements, 2)
+		require.Equal(t, "permission:345X:object:Opportunity:delete", entitlements[0].Id)
+		require.Equal(t, "type - name: Delete Opportunity", entitlements[0].DisplayName)
+		require.Equal(t, "Grants Delete on Opportunity records", entitlements[0].Description)
+		require.Equal(t, []*v2.ResourceType{resourceTypePermissionSet}, entitlements[0].GrantableTo)
+		require.Equal(t, "permission:345X:system:ModifyAllData", entitlements[1].Id)
+
+		var immutable v2.EntitlementImmutable
+		found, err := test.UnmarshalFromAnys(&immutable, entitlements[0].Annotations)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		for _, kind := range derivedPermissionKinds {
+			require.Equal(t, kind == objectPermissionKind, kind.describes(entitlements[0]))
+		}
+		require.True(t, isDerivedPermissionEntitlement(entitlements[1]))
+		require.False(t, isDerivedPermissionEntitlement(&v2.Entitlement{Id: "permission:345X:assigned"}))
+	})
+
+	t.Run("should grant the permission set expandable from the assignment", func(t *testing.T) {
+		grants := derivedPermissionGrants(permission, derived)
+		require.Len(t, grants, 2)
+		require.Equal(t, "permission:345X:object:Opportunity:delete", grants[0].Entitlement.Id)
+		require.Equal(t, resourceTypePermissionSet.Id, grants[0].Principal.Id.ResourceType)
+		require.Equal(t, "345X", grants[0].Principal.Id.Resource)
+
+		var expandable v2.GrantExpandable
+		found, err := test.UnmarshalFromAnys(&expandable, grants[1].Annotations)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, []string{"permission:345X:assigned"}, expandable.EntitlementIds)
+
+		var immutable v2.GrantImmutable
+		found, err = test.UnmarshalFromAnys(&immutable, grants[1].Annotations)
+		require.NoError(t, err)
+		require.True(t, found)
+	})
+}
+
+func TestDerivedPermissionKinds(t *testing.T) {
+	names := func(derived []derivedPermission) []string {
+		rv := make([]string, 0, len(derived))
+		for _, permission := range derived {
+			rv = append(rv, permission.entitlementName())
+		}
+		return rv
+	}
+
+	t.Run("object access levels", func(t *testing.T) {
+		derived := objectDerivedPermissions([]*client.ObjectPermission{
+			{SobjectType: "Opportunity", Read: true, Delete: true, ModifyAllRecords: true},
+		})
+		require.Equal(t, []string{
+			"object:Opportunity:read",
+			"object:Opportunity:delete",
+			"object:Opportunity:modify_all",
+		}, names(derived))
+		require.Equal(t, "Modify All Opportunity", derived[2].displayName)
+	})
+
+	t.Run("system permissions", func(t *testing.T) {
+		derived := systemDerivedPermissions([]string{"ModifyAllData", "ApiEnabled"})
+		require.Equal(t, []string{"system:ModifyAllData", "system:ApiEnabled"}, names(derived))
+		require.Equal(t, "ModifyAllData (sensitive)", derived[0].displayName)
+		require.Equal(t, "ApiEnabled", derived[1].displayName)
+	})
+
+	t.Run("field access levels", func(t *testing.T) {
+		derived := fieldDerivedPermissions([]*client.FieldPermission{
+			{Field: "Contact.SSN__c", Read: true},
+			{Field: "Account.Secret__c", Read: true, Edit: true},
+		})
+		require.Equal(t, []string{
+			"field:Contact.SSN__c:read",
+			"field:Account.Secret__c:read",
+			"field:Account.Secret__c:edit",
+		}, names(derived))
+		require.Equal(t, "Grants Edit on the Account.Secret__c field", derived[2].description)
+	})
+}
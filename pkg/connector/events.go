@@ -0,0 +1,526 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	accessChangesFeedID = "access_changes"
+
+	// accessChangesDefaultLookback is how far back the feed starts reading when
+	// the caller gives neither a cursor nor a start time.
+	accessChangesDefaultLookback = 24 * time.Hour
+)
+
+// auditActionKind groups the Setup Audit Trail actions the feed reacts to.
+type auditActionKind int
+
+const (
+	auditActionIgnored auditActionKind = iota
+	auditActionUserChange
+	auditActionProfileChange
+	auditActionRoleChange
+	auditActionPermissionSetRemoval
+	auditActionPermissionSetGroupRemoval
+)
+
+// usernamePattern finds the username in an audit trail Display string such as
+// "Changed profile for user jdoe@example.com from Standard User to Custom:
+// Sales". Salesforce usernames are always shaped like email addresses.
+var usernamePattern = regexp.MustCompile(`[A-Za-z0-9._%+'-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+`)
+
+// auditAccessChange describes how to find the access an audit trail action
+// changed for a user. pattern captures the name of the access taken away from
+// the Display text and, for moves, the name of the access given in its place.
+// Both are looked up by field on table.
+type auditAccessChange struct {
+	pattern      *regexp.Regexp
+	table        string
+	field        string
+	resourceType *v2.ResourceType
+	slug         string
+}
+
+// auditAccessChanges covers the actions that change access. Profile and role
+// changes name both the previous and the new profile or role ("... from
+// Standard User to Custom: Sales"); names can contain " to " as well, so the
+// shortest previous name is taken. A user without a role is changed "from
+// none", or "to none".
+var auditAccessChanges = map[auditActionKind]auditAccessChange{
+	auditActionProfileChange: {
+		pattern:      regexp.MustCompile(`(?i)^changed profile for user \S+ from (.+?) to (.+)$`),
+		table:        client.TableNameProfiles,
+		field:        "Name",
+		resourceType: resourceTypeProfile,
+		slug:         profileAssignmentEntitlementName,
+	},
+	auditActionRoleChange: {
+		pattern:      regexp.MustCompile(`(?i)^changed role for user \S+ from (.+?) to (.+)$`),
+		table:        client.TableNameRoles,
+		field:        "Name",
+		resourceType: resourceTypeRole,
+		slug:         roleAssignmentEntitlementName,
+	},
+	auditActionPermissionSetRemoval: {
+		pattern:      regexp.MustCompile(`(?i)^permission set (.+): unassigned from user `),
+		table:        client.TableNamePermissionsSets,
+		field:        "Label",
+		resourceType: resourceTypePermissionSet,
+		slug:         permissionSetAssignmentEntitlementName,
+	},
+	auditActionPermissionSetGroupRemoval: {
+		pattern:      regexp.MustCompile(`(?i)^permission set group (.+): unassigned from user `),
+		table:        client.TablePermissionSetGroup,
+		field:        "MasterLabel",
+		resourceType: resourceTypePermissionSetGroup,
+		slug:         permissionSetGroupMemberEntitlementName,
+	},
+}
+
+// names returns the name of the access the entry took away and of the access
+// it gave in its place. Either is "" when there was none or the Display text
+// doesn't say.
+func (c auditAccessChange) names(display string) (string, string) {
+	match := c.pattern.FindStringSubmatch(display)
+	if match == nil {
+		return "", ""
+	}
+	names := make([]string, 2)
+	for i, name := range match[1:] {
+		if !strings.EqualFold(name, "none") {
+			names[i] = name
+		}
+	}
+	return names[0], names[1]
+}
+
+// classifyAuditAction maps a Setup Audit Trail action to the kind of event it
+// produces. Salesforce doesn't publish a complete list of actions and their
+// casing varies, so matching is done on lowercased fragments.
+func classifyAuditAction(action string) auditActionKind {
+	action = strings.ToLower(action)
+	switch {
+	case strings.HasPrefix(action, "changedprofileforuser"):
+		return auditActionProfileChange
+	case strings.HasPrefix(action, "changedroleforuser"):
+		return auditActionRoleChange
+	case strings.HasPrefix(action, "permsetgroup") && strings.Contains(action, "unassign"):
+		return auditActionPermissionSetGroupRemoval
+	case strings.HasPrefix(action, "permset") && strings.Contains(action, "unassign"):
+		return auditActionPermissionSetRemoval
+	case strings.HasPrefix(action, "permset"),
+		strings.Contains(action, "froze"),
+		strings.Contains(action, "freeze"),
+		strings.HasSuffix(action, "activateduser"),
+		strings.HasPrefix(action, "changedusername"),
+		strings.HasPrefix(action, "changedemail"):
+		return auditActionUserChange
+	}
+	return auditActionIgnored
+}
+
+// accessChangesCursor is the feed's stream cursor: one position per source.
+type accessChangesCursor struct {
+	AuditTrail               client.ChangeCursor `json:"audit_trail"`
+	PermissionSetAssignments client.ChangeCursor `json:"permission_set_assignments"`
+	GroupMembers             client.ChangeCursor `json:"group_members"`
+}
+
+// accessChangesFeed turns Salesforce's change history into access events.
+// PermissionSetAssignment and GroupMember rows are read by SystemModstamp and
+// become grant events. The Setup Audit Trail fills in what those objects
+// can't: profile and role changes become grant events for the user's current
+// profile or role and revoke events for the previous one, permission set and
+// permission set group unassignments become revoke events, and every
+// user-level action the feed reacts to (including freezes and deactivations)
+// becomes a change event on the user so the next sync picks it up. Deleted
+// assignments and memberships leave no row behind to query, so revocations
+// only show up through the audit trail.
+type accessChangesFeed struct {
+	client     *client.SalesforceClient
+	syncQueues bool
+}
+
+func newAccessChangesFeed(client *client.SalesforceClient, syncQueues bool) *accessChangesFeed {
+	return &accessChangesFeed{
+		client:     client,
+		syncQueues: syncQueues,
+	}
+}
+
+// EventFeeds returns the event feeds the connector serves.
+func (d *Salesforce) EventFeeds(ctx context.Context) []connectorbuilder.EventFeed {
+	return []connectorbuilder.EventFeed{
+		newAccessChangesFeed(d.client, d.syncQueues),
+		newLoginActivityFeed(d.client, d.syncConnectedApps),
+	}
+}
+
+func (f *accessChangesFeed) EventFeedMetadata(ctx context.Context) *v2.EventFeedMetadata {
+	return &v2.EventFeedMetadata{
+		Id: accessChangesFeedID,
+		SupportedEventTypes: []v2.EventType{
+			v2.EventType_EVENT_TYPE_CREATE_GRANT,
+			v2.EventType_EVENT_TYPE_CREATE_REVOKE,
+			v2.EventType_EVENT_TYPE_RESOURCE_CHANGE,
+		},
+	}
+}
+
+func (f *accessChangesFeed) ListEvents(
+	ctx context.Context,
+	earliestEvent *timestamppb.Timestamp,
+	pToken *pagination.StreamToken,
+) (
+	[]*v2.Event,
+	*pagination.StreamState,
+	annotations.Annotations,
+	error,
+) {
+	cursor, err := parseAccessChangesCursor(pToken.Cursor, earliestEvent)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pageSize := pToken.Size
+	if pageSize <= 0 {
+		pageSize = client.PageSizeDefault
+	}
+
+	events := make([]*v2.Event, 0)
+
+	assignments, nextAssignments, moreAssignments, ratelimitData, err := f.client.GetPermissionSetAssignmentChanges(
+		ctx,
+		cursor.PermissionSetAssignments,
+		pageSize,
+	)
+	if err != nil {
+		return nil, nil, client.WithRateLimitAnnotations(ratelimitData), err
+	}
+	for _, assignment := range assignments {
+		events = append(events, permissionSetAssignmentEvent(assignment))
+	}
+
+	memberships, nextMembers, moreMembers, ratelimitData, err := f.client.GetGroupMembershipChanges(
+		ctx,
+		cursor.GroupMembers,
+		pageSize,
+	)
+	if err != nil {
+		return nil, nil, client.WithRateLimitAnnotations(ratelimitData), err
+	}
+	queueIDs, ratelimitData, err := f.queueIDs(ctx, memberships)
+	if err != nil {
+		return nil, nil, client.WithRateLimitAnnotations(ratelimitData), err
+	}
+	for _, membership := range memberships {
+		events = append(events, groupMembershipEvent(membership, queueIDs[membership.GroupID]))
+	}
+
+	entries, nextAuditTrail, moreAuditTrail, ratelimitData, err := f.client.GetSetupAuditTrail(
+		ctx,
+		cursor.AuditTrail,
+		pageSize,
+	)
+	if err != nil {
+		return nil, nil, client.WithRateLimitAnnotations(ratelimitData), err
+	}
+	auditEvents, ratelimitData, err := f.auditTrailEvents(ctx, entries)
+	if err != nil {
+		return nil, nil, client.WithRateLimitAnnotations(ratelimitData), err
+	}
+	events = append(events, auditEvents...)
+
+	nextCursor, err := json.Marshal(accessChangesCursor{
+		AuditTrail:               nextAuditTrail,
+		PermissionSetAssignments: nextAssignments,
+		GroupMembers:             nextMembers,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return events, &pagination.StreamState{
+		Cursor:  string(nextCursor),
+		HasMore: moreAssignments || moreMembers || moreAuditTrail,
+	}, client.WithRateLimitAnnotations(ratelimitData), nil
+}
+
+func parseAccessChangesCursor(
+	cursor string,
+	earliestEvent *timestamppb.Timestamp,
+) (*accessChangesCursor, error) {
+	if cursor != "" {
+		parsed := &accessChangesCursor{}
+		if err := json.Unmarshal([]byte(cursor), parsed); err != nil {
+			return nil, fmt.Errorf("salesforce-connector: invalid event feed cursor: %w", err)
+		}
+		return parsed, nil
+	}
+
+	start := time.Now().Add(-accessChangesDefaultLookback)
+	if earliestEvent != nil {
+		start = earliestEvent.AsTime()
+	}
+	startCursor := client.ChangeCursor{Time: start.UTC()}
+	return &accessChangesCursor{
+		AuditTrail:               startCursor,
+		PermissionSetAssignments: startCursor,
+		GroupMembers:             startCursor,
+	}, nil
+}
+
+func permissionSetAssignmentEvent(assignment *client.PermissionSetAssignment) *v2.Event {
+	var ent *v2.Entitlement
+	if assignment.PermissionSetGroupID != "" {
+		ent = eventEntitlement(
+			resourceTypePermissionSetGroup,
+			assignment.PermissionSetGroupID,
+			permissionSetGroupMemberEntitlementName,
+		)
+	} else {
+		ent = eventEntitlement(
+			resourceTypePermissionSet,
+			assignment.PermissionSetID,
+			permissionSetAssignmentEntitlementName,
+		)
+	}
+	return createGrantEvent(
+		changeEventID(client.TableNamePermissionAssignments, assignment.ID, assignment.ModifiedAt),
+		assignment.ModifiedAt,
+		ent,
+		eventResource(resourceTypeUser, assignment.UserID),
+	)
+}
+
+// queueIDs finds which of the memberships' groups are queues. Queues only
+// need telling apart when they are synced as their own resource type.
+func (f *accessChangesFeed) queueIDs(
+	ctx context.Context,
+	memberships []*client.SalesforceGroupMembership,
+) (
+	map[string]bool,
+	*v2.RateLimitDescription,
+	error,
+) {
+	if !f.syncQueues {
+		return map[string]bool{}, nil, nil
+	}
+	groupIDs := make([]string, 0, len(memberships))
+	for _, membership := range memberships {
+		if !slices.Contains(groupIDs, membership.GroupID) {
+			groupIDs = append(groupIDs, membership.GroupID)
+		}
+	}
+	return f.client.GetQueueIDs(ctx, groupIDs)
+}
+
+func groupMembershipEvent(membership *client.SalesforceGroupMembership, isQueue bool) *v2.Event {
+	principalType := resourceTypeUser
+	if membership.IsGroup {
+		principalType = resourceTypeGroup
+	}
+	ent := eventEntitlement(resourceTypeGroup, membership.GroupID, groupMemberEntitlementName)
+	if isQueue {
+		ent = eventEntitlement(resourceTypeQueue, membership.GroupID, queueMemberEntitlementName)
+	}
+	return createGrantEvent(
+		changeEventID(client.TableNameGroupMemberships, membership.ID, membership.ModifiedAt),
+		membership.ModifiedAt,
+		ent,
+		eventResource(principalType, membership.PrincipalID),
+	)
+}
+
+// auditTrailEvents maps the audit trail entries the feed cares about to
+// events. The audit trail only describes its target in the Display text, so
+// the user is found by the username in it and entries without one are skipped.
+// The access an entry took away or gave is likewise found by its name, and is
+// only revoked or granted when the name matches exactly one record. Events
+// follow the entry itself rather than the user's current access, which may
+// have changed again since.
+func (f *accessChangesFeed) auditTrailEvents(
+	ctx context.Context,
+	entries []*client.SetupAuditTrailEntry,
+) (
+	[]*v2.Event,
+	*v2.RateLimitDescription,
+	error,
+) {
+	logger := ctxzap.Extract(ctx)
+	usernames := make([]string, 0)
+	targets := make(map[string]string, len(entries))
+	changedNames := make(map[auditActionKind][]string)
+	for _, entry := range entries {
+		kind := classifyAuditAction(entry.Action)
+		if kind == auditActionIgnored {
+			continue
+		}
+		username := usernamePattern.FindString(entry.Display)
+		if username == "" {
+			logger.Debug(
+				"salesforce-connector: no username in audit trail entry, skipping",
+				zap.String("action", entry.Action),
+				zap.String("audit_trail_id", entry.ID),
+			)
+			continue
+		}
+		username = strings.ToLower(username)
+		targets[entry.ID] = username
+		if !slices.Contains(usernames, username) {
+			usernames = append(usernames, username)
+		}
+		if change, ok := auditAccessChanges[kind]; ok {
+			removed, added := change.names(entry.Display)
+			for _, name := range []string{removed, added} {
+				if name != "" && !slices.Contains(changedNames[kind], name) {
+					changedNames[kind] = append(changedNames[kind], name)
+				}
+			}
+		}
+	}
+	if len(usernames) == 0 {
+		return nil, nil, nil
+	}
+
+	users, ratelimitData, err := f.client.GetUsersByUsername(ctx, usernames)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	usersByUsername := make(map[string]*client.SalesforceUser, len(users))
+	for _, user := range users {
+		usersByUsername[strings.ToLower(user.Username)] = user
+	}
+
+	changedIDs := make(map[auditActionKind]map[string][]string, len(changedNames))
+	for kind, names := range changedNames {
+		change := auditAccessChanges[kind]
+		changedIDs[kind], ratelimitData, err = f.client.GetIDsByName(ctx, change.table, change.field, names)
+		if err != nil {
+			return nil, ratelimitData, err
+		}
+	}
+	// uniqueID returns the ID of the one record with the name, or "".
+	uniqueID := func(kind auditActionKind, name string) string {
+		ids := changedIDs[kind][strings.ToLower(name)]
+		if name == "" || len(ids) != 1 {
+			return ""
+		}
+		return ids[0]
+	}
+
+	events := make([]*v2.Event, 0)
+	for _, entry := range entries {
+		user, ok := usersByUsername[targets[entry.ID]]
+		if !ok {
+			continue
+		}
+		eventID := fmt.Sprintf("%s:%s", client.TableNameSetupAuditTrail, entry.ID)
+		principal := eventResource(resourceTypeUser, user.ID)
+
+		kind := classifyAuditAction(entry.Action)
+		if change, ok := auditAccessChanges[kind]; ok {
+			removed, added := change.names(entry.Display)
+			removedID := uniqueID(kind, removed)
+			addedID := uniqueID(kind, added)
+			// Profile and role names aren't unique, so a move between two
+			// records with the same name can resolve to one ID. Skip the revoke
+			// rather than take back the access just granted.
+			if removedID != "" && removedID != addedID {
+				events = append(events, createRevokeEvent(
+					eventID+":"+change.resourceType.Id+":revoke",
+					entry.CreatedDate,
+					eventEntitlement(change.resourceType, removedID, change.slug),
+					principal,
+				))
+			}
+			if addedID != "" {
+				events = append(events, createGrantEvent(
+					eventID+":"+change.resourceType.Id,
+					entry.CreatedDate,
+					eventEntitlement(change.resourceType, addedID, change.slug),
+					principal,
+				))
+			}
+		}
+		events = append(events, &v2.Event{
+			Id:         eventID,
+			OccurredAt: timestamppb.New(entry.CreatedDate),
+			Event: &v2.Event_ResourceChangeEvent{
+				ResourceChangeEvent: &v2.ResourceChangeEvent{
+					ResourceId: principal.Id,
+				},
+			},
+		})
+	}
+	return events, ratelimitData, nil
+}
+
+// changeEventID identifies one modification of a record, since the same
+// assignment can show up again when it is modified later.
+func changeEventID(table string, id string, modifiedAt time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", table, id, modifiedAt.UnixMilli())
+}
+
+func createGrantEvent(
+	id string,
+	occurredAt time.Time,
+	ent *v2.Entitlement,
+	principal *v2.Resource,
+) *v2.Event {
+	return &v2.Event{
+		Id:         id,
+		OccurredAt: timestamppb.New(occurredAt),
+		Event: &v2.Event_CreateGrantEvent{
+			CreateGrantEvent: &v2.CreateGrantEvent{
+				Entitlement: ent,
+				Principal:   principal,
+			},
+		},
+	}
+}
+
+func createRevokeEvent(
+	id string,
+	occurredAt time.Time,
+	ent *v2.Entitlement,
+	principal *v2.Resource,
+) *v2.Event {
+	return &v2.Event{
+		Id:         id,
+		OccurredAt: timestamppb.New(occurredAt),
+		Event: &v2.Event_CreateRevokeEvent{
+			CreateRevokeEvent: &v2.CreateRevokeEvent{
+				Entitlement: ent,
+				Principal:   principal,
+			},
+		},
+	}
+}
+
+func eventResource(resourceType *v2.ResourceType, id string) *v2.Resource {
+	return &v2.Resource{
+		Id: &v2.ResourceId{
+			ResourceType: resourceType.Id,
+			Resource:     id,
+		},
+	}
+}
+
+func eventEntitlement(resourceType *v2.ResourceType, id string, slug string) *v2.Entitlement {
+	return entitlement.NewAssignmentEntitlement(eventResource(resourceType, id), slug)
+}
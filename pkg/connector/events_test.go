@@ -0,0 +1,227 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	"github.com/conductorone/baton-salesforce/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestAccessChangesFeed(t *testing.T) {
+	ctx := context.Background()
+
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	// The audit trail fixtures refer to jdoe@example.com, who moved from the
+	// Standard User profile to 298X.
+	_, err = db.ExecContext(
+		ctx,
+		`UPDATE User SET "username" = 'jdoe@example.com', "profileid" = '298X' WHERE Id = '0053X'`,
+	)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE Profile SET "name" = 'Standard User' WHERE Id = '198X'`)
+	require.NoError(t, err)
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed := newAccessChangesFeed(salesforceClient, false)
+	start := timestamppb.New(time.Date(2025, 3, 26, 0, 0, 0, 0, time.UTC))
+
+	listAll := func(t *testing.T, pageSize int) ([]*v2.Event, string) {
+		events := make([]*v2.Event, 0)
+		pToken := &pagination.StreamToken{Size: pageSize}
+		for range 20 {
+			nextEvents, state, annos, err := feed.ListEvents(ctx, start, pToken)
+			require.NoError(t, err)
+			test.AssertNoRatelimitAnnotations(t, annos)
+			events = append(events, nextEvents...)
+			pToken.Cursor = state.Cursor
+			if !state.HasMore {
+				return events, state.Cursor
+			}
+		}
+		t.Fatal("event feed did not finish")
+		return nil, ""
+	}
+
+	t.Run("should emit grant events for assignments and memberships", func(t *testing.T) {
+		events, _ := listAll(t, 1)
+
+		grants := make(map[string]string)
+		for _, event := range events {
+			if created := event.GetCreateGrantEvent(); created != nil {
+				grants[created.Entitlement.Id] = created.Principal.Id.Resource
+			}
+		}
+		require.Equal(t, map[string]string{
+			"permission:345X:assigned":          "0051X",
+			"permission_set_group:PSG1X:member": "0051X",
+			"group:00G1X:member":                "0051X",
+			"group:00G3X:member":                "0052X",
+			"profile:298X:assigned":             "0053X",
+		}, grants)
+	})
+
+	t.Run("should emit queue grants when queues are synced on their own", func(t *testing.T) {
+		queueFeed := newAccessChangesFeed(salesforceClient, true)
+		events, _, _, err := queueFeed.ListEvents(ctx, start, &pagination.StreamToken{Size: 100})
+		require.NoError(t, err)
+
+		entitlementIDs := make([]string, 0)
+		for _, event := range events {
+			if created := event.GetCreateGrantEvent(); created != nil {
+				entitlementIDs = append(entitlementIDs, created.Entitlement.Id)
+			}
+		}
+		require.Contains(t, entitlementIDs, "queue:00G3X:member")
+		require.Contains(t, entitlementIDs, "group:00G1X:member")
+		require.NotContains(t, entitlementIDs, "group:00G3X:member")
+	})
+
+	t.Run("should emit user change events from the audit trail", func(t *testing.T) {
+		events, _ := listAll(t, 100)
+
+		changed := make([]string, 0)
+		for _, event := range events {
+			if change := event.GetResourceChangeEvent(); change != nil {
+				require.Equal(t, resourceTypeUser.Id, change.ResourceId.ResourceType)
+				changed = append(changed, change.ResourceId.Resource)
+			}
+		}
+		// The profile change and the permission set removal both target
+		// jdoe@example.com; the custom field and the unknown user are skipped.
+		require.Equal(t, []string{"0053X", "0053X"}, changed)
+	})
+
+	t.Run("should emit revoke events for unassignments and profile moves", func(t *testing.T) {
+		events, _ := listAll(t, 100)
+
+		revokes := make(map[string]string)
+		for _, event := range events {
+			if revoked := event.GetCreateRevokeEvent(); revoked != nil {
+				revokes[revoked.Entitlement.Id] = revoked.Principal.Id.Resource
+			}
+		}
+		require.Equal(t, map[string]string{
+			"permission:345X:assigned": "0053X",
+			"profile:198X:assigned":    "0053X",
+		}, revokes)
+	})
+
+	t.Run("should not repeat events after the cursor", func(t *testing.T) {
+		_, cursor := listAll(t, 100)
+
+		events, state, _, err := feed.ListEvents(ctx, start, &pagination.StreamToken{Size: 100, Cursor: cursor})
+		require.NoError(t, err)
+		require.Empty(t, events)
+		require.False(t, state.HasMore)
+	})
+}
+
+func TestAuditTrailEventsFollowEachEntry(t *testing.T) {
+	ctx := context.Background()
+
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	// jdoe@example.com moved from Standard User to Sales and on to Service,
+	// then back to Standard User after both entries were written.
+	_, err = db.ExecContext(
+		ctx,
+		`UPDATE User SET "username" = 'jdoe@example.com', "profileid" = '198X' WHERE Id = '0053X'`,
+	)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE Profile SET "name" = 'Standard User' WHERE Id = '198X'`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE Profile SET "name" = 'Sales' WHERE Id = '298X'`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO Profile (Id, Name, UserLicenseId) VALUES ('398X', 'Service', '100X')`)
+	require.NoError(t, err)
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed := newAccessChangesFeed(salesforceClient, false)
+
+	createdDate := time.Date(2025, 3, 27, 0, 0, 0, 0, time.UTC)
+	events, _, err := feed.auditTrailEvents(ctx, []*client.SetupAuditTrailEntry{
+		{
+			ID:          "0YM8X",
+			Action:      "changedprofileforuserstdtocust",
+			Display:     "Changed profile for user jdoe@example.com from Standard User to Sales",
+			CreatedDate: createdDate,
+		},
+		{
+			ID:          "0YM9X",
+			Action:      "changedprofileforusercusttocust",
+			Display:     "Changed profile for user jdoe@example.com from Sales to Service",
+			CreatedDate: createdDate.Add(time.Minute),
+		},
+	})
+	require.NoError(t, err)
+
+	grants := make([]string, 0)
+	revokes := make([]string, 0)
+	for _, event := range events {
+		if created := event.GetCreateGrantEvent(); created != nil {
+			require.Equal(t, "0053X", created.Principal.Id.Resource)
+			grants = append(grants, created.Entitlement.Id)
+		}
+		if revoked := event.GetCreateRevokeEvent(); revoked != nil {
+			require.Equal(t, "0053X", revoked.Principal.Id.Resource)
+			revokes = append(revokes, revoked.Entitlement.Id)
+		}
+	}
+	require.Equal(t, []string{"profile:298X:assigned", "profile:398X:assigned"}, grants)
+	require.Equal(t, []string{"profile:198X:assigned", "profile:298X:assigned"}, revokes)
+}
+
+func TestAuditChangeNames(t *testing.T) {
+	profileChange := auditAccessChanges[auditActionProfileChange]
+	removed, added := profileChange.names(
+		"Changed profile for user jdoe@example.com from Standard User to Custom: Sales to Service",
+	)
+	require.Equal(t, "Standard User", removed)
+	require.Equal(t, "Custom: Sales to Service", added)
+
+	roleChange := auditAccessChanges[auditActionRoleChange]
+	removed, added = roleChange.names("Changed role for user jdoe@example.com from none to CEO")
+	require.Equal(t, "", removed)
+	require.Equal(t, "CEO", added)
+	removed, added = roleChange.names("Changed role for user jdoe@example.com from CEO to none")
+	require.Equal(t, "CEO", removed)
+	require.Equal(t, "", added)
+
+	groupRemoval := auditAccessChanges[auditActionPermissionSetGroupRemoval]
+	removed, added = groupRemoval.names("Permission set group Sales Ops: unassigned from user jdoe@example.com")
+	require.Equal(t, "Sales Ops", removed)
+	require.Equal(t, "", added)
+}
+
+func TestClassifyAuditAction(t *testing.T) {
+	require.Equal(t, auditActionProfileChange, classifyAuditAction("changedprofileforuserstdtocust"))
+	require.Equal(t, auditActionRoleChange, classifyAuditAction("changedroleforuserfromnone"))
+	require.Equal(t, auditActionUserChange, classifyAuditAction("PermSetAssign"))
+	require.Equal(t, auditActionPermissionSetRemoval, classifyAuditAction("PermSetUnassign"))
+	require.Equal(t, auditActionPermissionSetGroupRemoval, classifyAuditAction("PermSetGroupUnassign"))
+	require.Equal(t, auditActionUserChange, classifyAuditAction("deactivateduser"))
+	require.Equal(t, auditActionIgnored, classifyAuditAction("createdcf"))
+}
@@ -0,0 +1,181 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const featureLicenseAssignmentEntitlementName = "assigned"
+
+// featureLicenseBuilder syncs feature licenses such as Marketing User. They
+// aren't records: each one is a checkbox on the user, so the resource is
+// identified by the User field and granting or revoking one flips it.
+type featureLicenseBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.SalesforceClient
+}
+
+func featureLicenseResource(license client.FeatureLicense) (*v2.Resource, error) {
+	assignedEntitlementID := fmt.Sprintf("%s:%s:%s",
+		resourceTypeFeatureLicense.Id,
+		license.Field,
+		featureLicenseAssignmentEntitlementName,
+	)
+
+	return rs.NewResource(
+		license.Name,
+		resourceTypeFeatureLicense,
+		license.Field,
+		rs.WithDescription(fmt.Sprintf("Assigned with the %s checkbox on the user", license.Field)),
+		rs.WithLicenseProfileTrait(
+			rs.WithLicenseName(license.Name),
+			rs.WithLicenseEntitlementIDs(assignedEntitlementID),
+		),
+	)
+}
+
+func (o *featureLicenseBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeFeatureLicense
+}
+
+func (o *featureLicenseBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	_ rs.SyncOpAttrs,
+) (
+	[]*v2.Resource,
+	*rs.SyncOpResults,
+	error,
+) {
+	rv := make([]*v2.Resource, 0, len(client.FeatureLicenses))
+	for _, license := range client.FeatureLicenses {
+		newResource, err := featureLicenseResource(license)
+		if err != nil {
+			return nil, &rs.SyncOpResults{}, err
+		}
+
+		rv = append(rv, newResource)
+	}
+	return rv, &rs.SyncOpResults{}, nil
+}
+
+func (o *featureLicenseBuilder) Entitlements(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ rs.SyncOpAttrs,
+) (
+	[]*v2.Entitlement,
+	*rs.SyncOpResults,
+	error,
+) {
+	entitlements := []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			featureLicenseAssignmentEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeUser),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s Feature License", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Has the %s feature license in Salesforce", resource.DisplayName),
+			),
+		),
+	}
+
+	return entitlements, nil, nil
+}
+
+func (o *featureLicenseBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	attrs rs.SyncOpAttrs,
+) (
+	[]*v2.Grant,
+	*rs.SyncOpResults,
+	error,
+) {
+	token := &attrs.PageToken
+	users, nextToken, ratelimitData, err := o.client.GetFeatureLicenseHolders(
+		ctx,
+		resource.Id.Resource,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, user := range users {
+		grants = append(grants, grant.NewGrant(
+			resource,
+			featureLicenseAssignmentEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceTypeUser.Id,
+				Resource:     user.ID,
+			},
+		))
+	}
+	return grants, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func (o *featureLicenseBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+) (annotations.Annotations, error) {
+	logger := ctxzap.Extract(ctx)
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		logger.Warn(
+			"salesforce-connector: only users can be granted feature licenses",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("salesforce-connector: only users can be granted feature licenses")
+	}
+
+	ratelimitData, err := o.client.SetUserFeatureLicense(
+		ctx,
+		principal.Id.Resource,
+		entitlement.Resource.Id.Resource,
+		true,
+	)
+	return client.WithRateLimitAnnotations(ratelimitData), err
+}
+
+func (o *featureLicenseBuilder) Revoke(
+	ctx context.Context,
+	grant *v2.Grant,
+) (annotations.Annotations, error) {
+	if grant.Principal.Id.ResourceType != resourceTypeUser.Id {
+		return nil, fmt.Errorf("salesforce-connector: only users can have feature licenses revoked")
+	}
+
+	ratelimitData, err := o.client.SetUserFeatureLicense(
+		ctx,
+		grant.Principal.Id.Resource,
+		grant.Entitlement.Resource.Id.Resource,
+		false,
+	)
+	return client.WithRateLimitAnnotations(ratelimitData), err
+}
+
+func newFeatureLicenseBuilder(client *client.SalesforceClient) *featureLicenseBuilder {
+	return &featureLicenseBuilder{
+		resourceType: resourceTypeFeatureLicense,
+		client:       client,
+	}
+}
@@ -0,0 +1,92 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	"github.com/conductorone/baton-salesforce/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureLicenses(t *testing.T) {
+	ctx := context.Background()
+
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	_, err = db.ExecContext(
+		ctx,
+		`UPDATE User SET "userpermissionsmarketinguser" = 1 WHERE Id = '0051X'`,
+	)
+	require.NoError(t, err)
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newFeatureLicenseBuilder(salesforceClient)
+
+	t.Run("should list one license per user checkbox", func(t *testing.T) {
+		resources, _, err := c.List(ctx, nil, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Len(t, resources, len(client.FeatureLicenses))
+		require.Equal(t, "Marketing User", resources[0].DisplayName)
+		require.Equal(t, "UserPermissionsMarketingUser", resources[0].Id.Resource)
+
+		trait, err := rs.GetLicenseProfileTrait(resources[0])
+		require.Nil(t, err)
+		require.Equal(t, []string{"feature_license:UserPermissionsMarketingUser:assigned"}, trait.GetEntitlementIds())
+	})
+
+	t.Run("should grant and revoke by flipping the checkbox", func(t *testing.T) {
+		license, _ := featureLicenseResource(client.FeatureLicenses[0])
+		user, _ := userResource(ctx, &client.SalesforceUser{ID: "0052X"}, nil, false)
+
+		ent := v2.Entitlement{
+			Id:       entitlement.NewEntitlementID(license, featureLicenseAssignmentEntitlementName),
+			Resource: license,
+		}
+
+		grantAnnotations, err := c.Grant(ctx, user, &ent)
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, grantAnnotations)
+
+		if err := uhttp.ClearCaches(ctx); err != nil {
+			t.Fatal(err)
+		}
+		grantsBefore, results, err := c.Grants(ctx, license, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Empty(t, results.NextPageToken)
+		require.Len(t, grantsBefore, 2)
+
+		revokeAnnotations, err := c.Revoke(ctx, &v2.Grant{Entitlement: &ent, Principal: user})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, revokeAnnotations)
+
+		if err := uhttp.ClearCaches(ctx); err != nil {
+			t.Fatal(err)
+		}
+		grantsAfter, _, err := c.Grants(ctx, license, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Len(t, grantsAfter, 1)
+		require.Equal(t, "0051X", grantsAfter[0].Principal.Id.Resource)
+	})
+
+	t.Run("should not touch fields that aren't feature licenses", func(t *testing.T) {
+		user, _ := userResource(ctx, &client.SalesforceUser{ID: "0052X"}, nil, false)
+		resource, _ := rs.NewResource("IsActive", resourceTypeFeatureLicense, "IsActive")
+
+		_, err := c.Grant(ctx, user, &v2.Entitlement{Resource: resource})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,107 @@
+package connector
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const fieldPermissionsProfileKey = "field_permissions"
+
+var fieldNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+\.[A-Za-z0-9_]+$`)
+
+// fieldAccessLevel is one of the FieldPermissions checkboxes.
+type fieldAccessLevel struct {
+	slug        string
+	displayName string
+	isGranted   func(*client.FieldPermission) bool
+}
+
+var fieldAccessLevels = []fieldAccessLevel{
+	{"read", "Read", func(p *client.FieldPermission) bool { return p.Read }},
+	{"edit", "Edit", func(p *client.FieldPermission) bool { return p.Edit }},
+}
+
+// parseFieldNames checks the configured fields are qualified with their
+// object, e.g. Contact.SSN__c. The names end up in SOQL, so anything that
+// isn't a plain identifier is rejected.
+func parseFieldNames(names []string) ([]string, error) {
+	rv := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !fieldNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("baton-salesforce: invalid field name %q, expected SobjectType.Field", name)
+		}
+		if !slices.Contains(rv, name) {
+			rv = append(rv, name)
+		}
+	}
+	return rv, nil
+}
+
+// fieldPermissionsProfile records the access a permission set gives on the
+// configured fields in its resource profile, keyed by field and then by access
+// level, so that its entitlements and grants don't need to query it again.
+func fieldPermissionsProfile(permissions []*client.FieldPermission) map[string]interface{} {
+	fields := make(map[string]interface{}, len(permissions))
+	for _, permission := range permissions {
+		levels := make(map[string]interface{}, len(fieldAccessLevels))
+		for _, level := range fieldAccessLevels {
+			levels[level.slug] = level.isGranted(permission)
+		}
+		fields[permission.Field] = levels
+	}
+	return map[string]interface{}{fieldPermissionsProfileKey: fields}
+}
+
+// resourceFieldPermissions reads back the field access recorded by
+// fieldPermissionsProfile, ordered by field.
+func resourceFieldPermissions(resource *v2.Resource) []*client.FieldPermission {
+	profile := rs.GetProfile(resource)
+	if profile == nil {
+		return nil
+	}
+	fields := profile.GetFields()[fieldPermissionsProfileKey].GetStructValue().GetFields()
+	permissions := make([]*client.FieldPermission, 0, len(fields))
+	for field, value := range fields {
+		levels := value.GetStructValue().GetFields()
+		permissions = append(permissions, &client.FieldPermission{
+			PermissionSetID: resource.Id.Resource,
+			Field:           field,
+			Read:            levels["read"].GetBoolValue(),
+			Edit:            levels["edit"].GetBoolValue(),
+		})
+	}
+	slices.SortFunc(permissions, func(a, b *client.FieldPermission) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+	return permissions
+}
+
+// fieldDerivedPermissions returns every access level the permission set
+// gives on each field, e.g. "field:Contact.SSN__c:read".
+func fieldDerivedPermissions(permissions []*client.FieldPermission) []derivedPermission {
+	derived := make([]derivedPermission, 0)
+	for _, permission := range permissions {
+		for _, level := range fieldAccessLevels {
+			if !level.isGranted(permission) {
+				continue
+			}
+			derived = append(derived, derivedPermission{
+				kind:        fieldPermissionKind,
+				name:        fmt.Sprintf("%s:%s", permission.Field, level.slug),
+				displayName: fmt.Sprintf("%s %s", level.displayName, permission.Field),
+				description: fmt.Sprintf("Grants %s on the %s field", level.displayName, permission.Field),
+			})
+		}
+	}
+	return derived
+}
@@ -2,6 +2,7 @@ package connector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/conductorone/baton-salesforce/pkg/connector/client"
@@ -22,6 +23,8 @@ const (
 type groupBuilder struct {
 	resourceType *v2.ResourceType
 	client       *client.SalesforceClient
+	// syncQueues leaves queues out of the groups; queueBuilder syncs them.
+	syncQueues bool
 }
 
 func getGroupName(group *client.SalesforceGroup) string {
@@ -68,6 +71,7 @@ func (o *groupBuilder) List(
 		ctx,
 		token.Token,
 		token.Size,
+		o.syncQueues,
 	)
 	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
 	if err != nil {
@@ -188,7 +192,14 @@ func (o *groupBuilder) Grant(
 		entitlement.Resource.Id.Resource,
 	)
 	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
-	return outputAnnotations, err
+	if err != nil {
+		if errors.Is(err, client.ErrObjectAlreadyExists) {
+			outputAnnotations.Append(&v2.GrantAlreadyExists{})
+			return outputAnnotations, nil
+		}
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
 }
 
 func (o *groupBuilder) Revoke(
@@ -211,9 +222,10 @@ func (o *groupBuilder) Revoke(
 	return outputAnnotations, nil
 }
 
-func newGroupBuilder(client *client.SalesforceClient) *groupBuilder {
+func newGroupBuilder(client *client.SalesforceClient, syncQueues bool) *groupBuilder {
 	return &groupBuilder{
 		resourceType: resourceTypeGroup,
 		client:       client,
+		syncQueues:   syncQueues,
 	}
 }
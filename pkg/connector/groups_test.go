@@ -28,7 +28,7 @@ func TestGroupsList(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	c := newGroupBuilder(salesforceClient)
+	c := newGroupBuilder(salesforceClient, true)
 
 	t.Run("should get groups with pagination", func(t *testing.T) {
 		resources := make([]*v2.Resource, 0)
@@ -55,6 +55,17 @@ func TestGroupsList(t *testing.T) {
 		require.NotEmpty(t, resources[0].Id)
 	})
 
+	t.Run("should list queues as groups unless they are synced on their own", func(t *testing.T) {
+		resources, results, err := newGroupBuilder(salesforceClient, false).List(
+			ctx,
+			nil,
+			rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}},
+		)
+		require.Nil(t, err)
+		require.Empty(t, results.NextPageToken)
+		require.Len(t, resources, 3)
+	})
+
 	t.Run("should grant and revoke entitlements", func(t *testing.T) {
 		group, _ := groupResource(&client.SalesforceGroup{ID: "00G1X"})
 		user, _ := userResource(ctx, &client.SalesforceUser{ID: "0052X"}, nil, false)
@@ -0,0 +1,82 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-salesforce/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLoginActivityFeed(t *testing.T) {
+	ctx := context.Background()
+
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := timestamppb.New(time.Date(2025, 3, 26, 0, 0, 0, 0, time.UTC))
+
+	listAll := func(t *testing.T, feed *loginActivityFeed, pageSize int) []*v2.Event {
+		events := make([]*v2.Event, 0)
+		pToken := &pagination.StreamToken{Size: pageSize}
+		for range 20 {
+			nextEvents, state, annos, err := feed.ListEvents(ctx, start, pToken)
+			require.NoError(t, err)
+			test.AssertNoRatelimitAnnotations(t, annos)
+			events = append(events, nextEvents...)
+			pToken.Cursor = state.Cursor
+			if !state.HasMore {
+				return events
+			}
+		}
+		t.Fatal("event feed did not finish")
+		return nil
+	}
+
+	t.Run("should emit a usage event per login in order", func(t *testing.T) {
+		events := listAll(t, newLoginActivityFeed(salesforceClient, false), 1)
+
+		require.Len(t, events, 3)
+		ids := make([]string, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.Id)
+			usage := event.GetUsageEvent()
+			require.NotNil(t, usage)
+			require.Equal(t, resourceTypeUser.Id, usage.ActorResource.Id.ResourceType)
+			require.Nil(t, usage.TargetResource)
+		}
+		require.Equal(t, []string{"LoginHistory:0Ya1X", "LoginHistory:0Ya2X", "LoginHistory:0Ya3X"}, ids)
+
+		details := &structpb.Struct{}
+		found, err := test.UnmarshalFromAnys(details, events[1].Annotations)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "Invalid Password", details.Fields["status"].GetStringValue())
+		require.Equal(t, "198.51.100.23", details.Fields["source_ip"].GetStringValue())
+		require.Equal(t, "Windows 10", details.Fields["platform"].GetStringValue())
+	})
+
+	t.Run("should target the connected app a login went through", func(t *testing.T) {
+		events := listAll(t, newLoginActivityFeed(salesforceClient, true), 100)
+
+		require.Len(t, events, 3)
+		require.Nil(t, events[0].GetUsageEvent().TargetResource)
+		target := events[2].GetUsageEvent().TargetResource
+		require.NotNil(t, target)
+		require.Equal(t, resourceTypeConnectedApplication.Id, target.Id.ResourceType)
+		require.Equal(t, "0H41X", target.Id.Resource)
+	})
+}
@@ -0,0 +1,45 @@
+package connector
+
+import (
+	"fmt"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+)
+
+const objectPermissionPageTokenPrefix = "obj:"
+
+// objectAccessLevel is one of the ObjectPermissions checkboxes.
+type objectAccessLevel struct {
+	slug        string
+	displayName string
+	isGranted   func(*client.ObjectPermission) bool
+}
+
+var objectAccessLevels = []objectAccessLevel{
+	{"read", "Read", func(p *client.ObjectPermission) bool { return p.Read }},
+	{"create", "Create", func(p *client.ObjectPermission) bool { return p.Create }},
+	{"edit", "Edit", func(p *client.ObjectPermission) bool { return p.Edit }},
+	{"delete", "Delete", func(p *client.ObjectPermission) bool { return p.Delete }},
+	{"view_all", "View All", func(p *client.ObjectPermission) bool { return p.ViewAllRecords }},
+	{"modify_all", "Modify All", func(p *client.ObjectPermission) bool { return p.ModifyAllRecords }},
+}
+
+// objectDerivedPermissions returns every access level the permission set
+// gives on each object, e.g. "object:Opportunity:delete".
+func objectDerivedPermissions(permissions []*client.ObjectPermission) []derivedPermission {
+	derived := make([]derivedPermission, 0)
+	for _, permission := range permissions {
+		for _, level := range objectAccessLevels {
+			if !level.isGranted(permission) {
+				continue
+			}
+			derived = append(derived, derivedPermission{
+				kind:        objectPermissionKind,
+				name:        fmt.Sprintf("%s:%s", permission.SobjectType, level.slug),
+				displayName: fmt.Sprintf("%s %s", level.displayName, permission.SobjectType),
+				description: fmt.Sprintf("Grants %s on %s records", level.displayName, permission.SobjectType),
+			})
+		}
+	}
+	return derived
+}
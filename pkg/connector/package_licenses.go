@@ -0,0 +1,223 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const packageLicenseAssignmentEntitlementName = "assigned"
+
+// packageLicenseBuilder syncs the seat licenses of installed managed
+// packages, e.g. CPQ or DocuSign, along with who is assigned a seat.
+type packageLicenseBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.SalesforceClient
+}
+
+func packageLicenseResource(license *client.PackageLicense) (*v2.Resource, error) {
+	assignedEntitlementID := fmt.Sprintf("%s:%s:%s",
+		resourceTypePackageLicense.Id,
+		license.ID,
+		packageLicenseAssignmentEntitlementName,
+	)
+	// Site licenses cover every user, so there is no seat count to report.
+	allowedLicenses := license.AllowedLicenses
+	description := fmt.Sprintf("%d of %d licenses used", license.UsedLicenses, allowedLicenses)
+	if license.IsSiteLicense() {
+		allowedLicenses = 0
+		description = "Site license"
+	}
+	if license.Status != "" {
+		description = fmt.Sprintf("%s (%s)", description, license.Status)
+	}
+	if license.ExpirationDate != "" {
+		description = fmt.Sprintf("%s, expires %s", description, license.ExpirationDate)
+	}
+
+	return rs.NewResource(
+		license.NamespacePrefix,
+		resourceTypePackageLicense,
+		license.ID,
+		rs.WithDescription(description),
+		rs.WithLicenseProfileTrait(
+			rs.WithLicenseName(license.NamespacePrefix),
+			rs.WithLicenseSeats(allowedLicenses, license.UsedLicenses),
+			rs.WithLicenseEntitlementIDs(assignedEntitlementID),
+		),
+	)
+}
+
+func (o *packageLicenseBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypePackageLicense
+}
+
+func (o *packageLicenseBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	attrs rs.SyncOpAttrs,
+) (
+	[]*v2.Resource,
+	*rs.SyncOpResults,
+	error,
+) {
+	token := &attrs.PageToken
+	licenses, nextToken, ratelimitData, err := o.client.GetPackageLicenses(
+		ctx,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	rv := make([]*v2.Resource, 0)
+	for _, license := range licenses {
+		newResource, err := packageLicenseResource(license)
+		if err != nil {
+			return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+		}
+
+		rv = append(rv, newResource)
+	}
+	return rv, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func (o *packageLicenseBuilder) Entitlements(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ rs.SyncOpAttrs,
+) (
+	[]*v2.Entitlement,
+	*rs.SyncOpResults,
+	error,
+) {
+	entitlements := []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			packageLicenseAssignmentEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeUser),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s Package License", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Has a seat of the %s managed package in Salesforce", resource.DisplayName),
+			),
+		),
+	}
+
+	return entitlements, nil, nil
+}
+
+func (o *packageLicenseBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	attrs rs.SyncOpAttrs,
+) (
+	[]*v2.Grant,
+	*rs.SyncOpResults,
+	error,
+) {
+	token := &attrs.PageToken
+	assignments, nextToken, ratelimitData, err := o.client.GetUserPackageLicenses(
+		ctx,
+		resource.Id.Resource,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, assignment := range assignments {
+		grants = append(grants, grant.NewGrant(
+			resource,
+			packageLicenseAssignmentEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceTypeUser.Id,
+				Resource:     assignment.UserID,
+			},
+		))
+	}
+	return grants, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func (o *packageLicenseBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+) (annotations.Annotations, error) {
+	logger := ctxzap.Extract(ctx)
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		logger.Warn(
+			"salesforce-connector: only users can be granted package licenses",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("salesforce-connector: only users can be granted package licenses")
+	}
+
+	ratelimitData, err := o.client.AddUserToPackageLicense(
+		ctx,
+		principal.Id.Resource,
+		entitlement.Resource.Id.Resource,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		if errors.Is(err, client.ErrObjectAlreadyExists) {
+			outputAnnotations.Append(&v2.GrantAlreadyExists{})
+			return outputAnnotations, nil
+		}
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
+}
+
+func (o *packageLicenseBuilder) Revoke(
+	ctx context.Context,
+	grant *v2.Grant,
+) (annotations.Annotations, error) {
+	if grant.Principal.Id.ResourceType != resourceTypeUser.Id {
+		return nil, fmt.Errorf("salesforce-connector: only users can have package licenses revoked")
+	}
+
+	ratelimitData, err := o.client.RemoveUserFromPackageLicense(
+		ctx,
+		grant.Principal.Id.Resource,
+		grant.Entitlement.Resource.Id.Resource,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		if errors.Is(err, client.ErrObjectNotFound) {
+			outputAnnotations.Append(&v2.GrantAlreadyRevoked{})
+			return outputAnnotations, nil
+		}
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
+}
+
+func newPackageLicenseBuilder(client *client.SalesforceClient) *packageLicenseBuilder {
+	return &packageLicenseBuilder{
+		resourceType: resourceTypePackageLicense,
+		client:       client,
+	}
+}
@@ -0,0 +1,97 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	"github.com/conductorone/baton-salesforce/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageLicenses(t *testing.T) {
+	ctx := context.Background()
+
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newPackageLicenseBuilder(salesforceClient)
+
+	t.Run("should list licenses with seat counts", func(t *testing.T) {
+		resources, results, err := c.List(ctx, nil, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.NotNil(t, results)
+		test.AssertNoRatelimitAnnotations(t, results.Annotations)
+		require.Empty(t, results.NextPageToken)
+
+		require.Len(t, resources, 2)
+		require.Equal(t, "SBQQ", resources[0].DisplayName)
+		require.Equal(t, "1 of 25 licenses used (Active)", resources[0].Description)
+
+		trait, err := rs.GetLicenseProfileTrait(resources[0])
+		require.Nil(t, err)
+		require.Equal(t, int64(25), trait.GetPurchasedSeats())
+		require.Equal(t, int64(1), trait.GetConsumedSeats())
+		require.Equal(t, []string{"package_license:050A1X:assigned"}, trait.GetEntitlementIds())
+
+		require.Equal(t, "dsfs", resources[1].DisplayName)
+		require.Equal(t, "Site license (Active)", resources[1].Description)
+		trait, err = rs.GetLicenseProfileTrait(resources[1])
+		require.Nil(t, err)
+		require.Equal(t, int64(0), trait.GetPurchasedSeats())
+	})
+
+	t.Run("should grant and revoke licenses", func(t *testing.T) {
+		license, _ := packageLicenseResource(&client.PackageLicense{ID: "050A1X"})
+		user, _ := userResource(ctx, &client.SalesforceUser{ID: "0052X"}, nil, false)
+
+		ent := v2.Entitlement{
+			Id:       entitlement.NewEntitlementID(license, packageLicenseAssignmentEntitlementName),
+			Resource: license,
+		}
+
+		grantAnnotations, err := c.Grant(ctx, user, &ent)
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, grantAnnotations)
+
+		grantsBefore, results, err := c.Grants(ctx, license, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Empty(t, results.NextPageToken)
+		require.Len(t, grantsBefore, 2)
+
+		if err := uhttp.ClearCaches(ctx); err != nil {
+			t.Fatal(err)
+		}
+		revokeAnnotations, err := c.Revoke(ctx, &v2.Grant{Entitlement: &ent, Principal: user})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, revokeAnnotations)
+
+		if err := uhttp.ClearCaches(ctx); err != nil {
+			t.Fatal(err)
+		}
+		grantsAfter, _, err := c.Grants(ctx, license, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Len(t, grantsAfter, 1)
+		require.Equal(t, "0051X", grantsAfter[0].Principal.Id.Resource)
+
+		revokeAnnotations, err = c.Revoke(ctx, &v2.Grant{Entitlement: &ent, Principal: user})
+		require.Nil(t, err)
+		var alreadyRevoked v2.GrantAlreadyRevoked
+		found, err := test.UnmarshalFromAnys(&alreadyRevoked, revokeAnnotations)
+		require.Nil(t, err)
+		require.True(t, found)
+	})
+}
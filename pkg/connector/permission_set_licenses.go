@@ -0,0 +1,217 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const permissionSetLicenseAssignmentEntitlementName = "assigned"
+
+// permissionSetLicenseBuilder syncs permission set licenses, the seats for
+// paid add-on features, along with who is assigned one.
+type permissionSetLicenseBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.SalesforceClient
+}
+
+func permissionSetLicenseResource(license *client.PermissionSetLicense) (*v2.Resource, error) {
+	assignedEntitlementID := fmt.Sprintf("%s:%s:%s",
+		resourceTypePermissionSetLicense.Id,
+		license.ID,
+		permissionSetLicenseAssignmentEntitlementName,
+	)
+	description := fmt.Sprintf("%d of %d licenses used", license.UsedLicenses, license.TotalLicenses)
+	if license.Status != "" {
+		description = fmt.Sprintf("%s (%s)", description, license.Status)
+	}
+	if license.ExpirationDate != "" {
+		description = fmt.Sprintf("%s, expires %s", description, license.ExpirationDate)
+	}
+
+	return rs.NewResource(
+		license.Name,
+		resourceTypePermissionSetLicense,
+		license.ID,
+		rs.WithDescription(description),
+		rs.WithLicenseProfileTrait(
+			rs.WithLicenseName(license.Name),
+			rs.WithLicenseSeats(license.TotalLicenses, license.UsedLicenses),
+			rs.WithLicenseEntitlementIDs(assignedEntitlementID),
+		),
+	)
+}
+
+func (o *permissionSetLicenseBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypePermissionSetLicense
+}
+
+func (o *permissionSetLicenseBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	attrs rs.SyncOpAttrs,
+) (
+	[]*v2.Resource,
+	*rs.SyncOpResults,
+	error,
+) {
+	token := &attrs.PageToken
+	licenses, nextToken, ratelimitData, err := o.client.GetPermissionSetLicenses(
+		ctx,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	rv := make([]*v2.Resource, 0)
+	for _, license := range licenses {
+		newResource, err := permissionSetLicenseResource(license)
+		if err != nil {
+			return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+		}
+
+		rv = append(rv, newResource)
+	}
+	return rv, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func (o *permissionSetLicenseBuilder) Entitlements(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ rs.SyncOpAttrs,
+) (
+	[]*v2.Entitlement,
+	*rs.SyncOpResults,
+	error,
+) {
+	entitlements := []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			permissionSetLicenseAssignmentEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeUser),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s Permission Set License", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Has the %s permission set license in Salesforce", resource.DisplayName),
+			),
+		),
+	}
+
+	return entitlements, nil, nil
+}
+
+func (o *permissionSetLicenseBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	attrs rs.SyncOpAttrs,
+) (
+	[]*v2.Grant,
+	*rs.SyncOpResults,
+	error,
+) {
+	token := &attrs.PageToken
+	assignments, nextToken, ratelimitData, err := o.client.GetPermissionSetLicenseAssignments(
+		ctx,
+		resource.Id.Resource,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, assignment := range assignments {
+		grants = append(grants, grant.NewGrant(
+			resource,
+			permissionSetLicenseAssignmentEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceTypeUser.Id,
+				Resource:     assignment.UserID,
+			},
+		))
+	}
+	return grants, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func (o *permissionSetLicenseBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+) (annotations.Annotations, error) {
+	logger := ctxzap.Extract(ctx)
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		logger.Warn(
+			"salesforce-connector: only users can be granted permission set licenses",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("salesforce-connector: only users can be granted permission set licenses")
+	}
+
+	ratelimitData, err := o.client.AddUserToPermissionSetLicense(
+		ctx,
+		principal.Id.Resource,
+		entitlement.Resource.Id.Resource,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		if errors.Is(err, client.ErrObjectAlreadyExists) {
+			outputAnnotations.Append(&v2.GrantAlreadyExists{})
+			return outputAnnotations, nil
+		}
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
+}
+
+func (o *permissionSetLicenseBuilder) Revoke(
+	ctx context.Context,
+	grant *v2.Grant,
+) (annotations.Annotations, error) {
+	if grant.Principal.Id.ResourceType != resourceTypeUser.Id {
+		return nil, fmt.Errorf("salesforce-connector: only users can have permission set licenses revoked")
+	}
+
+	ratelimitData, err := o.client.RemoveUserFromPermissionSetLicense(
+		ctx,
+		grant.Principal.Id.Resource,
+		grant.Entitlement.Resource.Id.Resource,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		if errors.Is(err, client.ErrObjectNotFound) {
+			outputAnnotations.Append(&v2.GrantAlreadyRevoked{})
+			return outputAnnotations, nil
+		}
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
+}
+
+func newPermissionSetLicenseBuilder(client *client.SalesforceClient) *permissionSetLicenseBuilder {
+	return &permissionSetLicenseBuilder{
+		resourceType: resourceTypePermissionSetLicense,
+		client:       client,
+	}
+}
@@ -2,7 +2,9 @@ package connector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/conductorone/baton-salesforce/pkg/connector/client"
@@ -20,16 +22,45 @@ const (
 	permPsgPageTokenPrefix                 = "psg:"
 )
 
+// permissionBuilder syncs permission sets, including the ones owned by
+// profiles. Besides the assignment, a permission set gets a read-only
+// entitlement for each piece of access its configuration gives (see
+// derived_permissions.go): with syncObjectPermissions set, every object access
+// level; each of the high-risk systemPermissions it turns on; and access to
+// each of the fieldPermissions. System and field permissions are recorded on
+// the resource when it is listed.
 type permissionBuilder struct {
-	resourceType *v2.ResourceType
-	client       *client.SalesforceClient
+	resourceType          *v2.ResourceType
+	client                *client.SalesforceClient
+	syncObjectPermissions bool
+	systemPermissions     []string
+	fieldPermissions      []string
 }
 
-func permissionResource(permission *client.SalesforcePermission) (*v2.Resource, error) {
+func permissionResource(
+	permission *client.SalesforcePermission,
+	fieldPermissions []*client.FieldPermission,
+) (*v2.Resource, error) {
+	options := make([]rs.ResourceOption, 0)
+	profile := make(map[string]interface{})
+	if len(permission.SystemPermissions) > 0 {
+		options = append(options, rs.WithDescription(
+			fmt.Sprintf("High-risk system permissions: %s", strings.Join(permission.SystemPermissions, ", ")),
+		))
+		maps.Copy(profile, systemPermissionsProfile(permission.SystemPermissions))
+	}
+	if len(fieldPermissions) > 0 {
+		maps.Copy(profile, fieldPermissionsProfile(fieldPermissions))
+	}
+	if len(profile) > 0 {
+		options = append(options, rs.WithResourceProfile(profile))
+	}
+
 	newPermissionResource, err := rs.NewResource(
 		fmt.Sprintf("%s - %s", permission.Type, permission.Name),
 		resourceTypePermissionSet,
 		permission.ID,
+		options...,
 	)
 	if err != nil {
 		return nil, err
@@ -56,15 +87,36 @@ func (o *permissionBuilder) List(
 		ctx,
 		token.Token,
 		token.Size,
+		o.systemPermissions,
 	)
 	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
 	if err != nil {
 		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
 	}
 
+	// Look up the field access of the whole page at once, so that the
+	// entitlements and grants of each permission set can read it back from
+	// the resource.
+	permissionSetIDs := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permissionSetIDs = append(permissionSetIDs, permission.ID)
+	}
+	fieldPermissions, ratelimitData, err := o.client.GetFieldPermissions(ctx, permissionSetIDs, o.fieldPermissions)
+	outputAnnotations = client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+	fieldPermissionsBySet := make(map[string][]*client.FieldPermission)
+	for _, fieldPermission := range fieldPermissions {
+		fieldPermissionsBySet[fieldPermission.PermissionSetID] = append(
+			fieldPermissionsBySet[fieldPermission.PermissionSetID],
+			fieldPermission,
+		)
+	}
+
 	rv := make([]*v2.Resource, 0)
 	for _, permission := range permissions {
-		newResource, err := permissionResource(permission)
+		newResource, err := permissionResource(permission, fieldPermissionsBySet[permission.ID])
 		if err != nil {
 			return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
 		}
@@ -80,7 +132,7 @@ func (o *permissionBuilder) List(
 func (o *permissionBuilder) Entitlements(
 	ctx context.Context,
 	resource *v2.Resource,
-	_ rs.SyncOpAttrs,
+	attrs rs.SyncOpAttrs,
 ) (
 	[]*v2.Entitlement,
 	*rs.SyncOpResults,
@@ -92,21 +144,54 @@ func (o *permissionBuilder) Entitlements(
 		zap.String("resource.DisplayName", resource.DisplayName),
 		zap.String("resource.Id.Resource", resource.Id.Resource),
 	)
-	entitlements := []*v2.Entitlement{
-		entitlement.NewAssignmentEntitlement(
+	token := &attrs.PageToken
+	entitlements := make([]*v2.Entitlement, 0)
+	var outputAnnotations annotations.Annotations
+	if token.Token == "" {
+		enabled := resourceSystemPermissions(resource)
+		description := fmt.Sprintf("Has the %s permission set in Salesforce", resource.DisplayName)
+		if len(enabled) > 0 {
+			description = fmt.Sprintf(
+				"%s, including the high-risk system permissions %s",
+				description,
+				strings.Join(enabled, ", "),
+			)
+		}
+		entitlements = append(entitlements, entitlement.NewAssignmentEntitlement(
 			resource,
 			permissionSetAssignmentEntitlementName,
 			entitlement.WithGrantableTo(resourceTypeUser, resourceTypePermissionSetGroup),
 			entitlement.WithDisplayName(
 				fmt.Sprintf("%s Permission Set", resource.DisplayName),
 			),
-			entitlement.WithDescription(
-				fmt.Sprintf("Has the %s permission set in Salesforce", resource.DisplayName),
-			),
-		),
+			entitlement.WithDescription(description),
+		))
+		entitlements = append(entitlements, derivedPermissionEntitlements(resource, systemDerivedPermissions(enabled))...)
+		entitlements = append(entitlements, derivedPermissionEntitlements(
+			resource,
+			fieldDerivedPermissions(resourceFieldPermissions(resource)),
+		)...)
+	}
+	if !o.syncObjectPermissions {
+		return entitlements, &rs.SyncOpResults{Annotations: outputAnnotations}, nil
 	}
 
-	return entitlements, nil, nil
+	objectPermissions, nextToken, ratelimitData, err := o.client.GetObjectPermissions(
+		ctx,
+		resource.Id.Resource,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations = client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+	entitlements = append(entitlements, derivedPermissionEntitlements(resource, objectDerivedPermissions(objectPermissions))...)
+
+	return entitlements, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
 }
 
 func (o *permissionBuilder) Grants(
@@ -123,6 +208,31 @@ func (o *permissionBuilder) Grants(
 
 	grants := make([]*v2.Grant, 0)
 
+	if strings.HasPrefix(pageToken, objectPermissionPageTokenPrefix) {
+		// Phase 3: object access, granted to the permission set itself
+		objectToken := strings.TrimPrefix(pageToken, objectPermissionPageTokenPrefix)
+		objectPermissions, nextObjectToken, ratelimitData, err := o.client.GetObjectPermissions(
+			ctx,
+			resource.Id.Resource,
+			objectToken,
+			token.Size,
+		)
+		outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+		if err != nil {
+			return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+		}
+		grants = append(grants, derivedPermissionGrants(resource, objectDerivedPermissions(objectPermissions))...)
+
+		var nextToken string
+		if nextObjectToken != "" {
+			nextToken = objectPermissionPageTokenPrefix + nextObjectToken
+		}
+		return grants, &rs.SyncOpResults{
+			NextPageToken: nextToken,
+			Annotations:   outputAnnotations,
+		}, nil
+	}
+
 	if strings.HasPrefix(pageToken, permPsgPageTokenPrefix) {
 		// Phase 2: PSG components that include this permission set
 		psgToken := strings.TrimPrefix(pageToken, permPsgPageTokenPrefix)
@@ -159,6 +269,8 @@ func (o *permissionBuilder) Grants(
 		var nextToken string
 		if nextPsgToken != "" {
 			nextToken = permPsgPageTokenPrefix + nextPsgToken
+		} else if o.syncObjectPermissions {
+			nextToken = objectPermissionPageTokenPrefix
 		}
 		return grants, &rs.SyncOpResults{
 			NextPageToken: nextToken,
@@ -166,7 +278,17 @@ func (o *permissionBuilder) Grants(
 		}, nil
 	}
 
-	// Phase 1: direct user assignments
+	// Phase 1: direct user assignments, plus the system and field permissions
+	// granted to the permission set itself on the first page
+	if pageToken == "" {
+		enabled := resourceSystemPermissions(resource)
+		grants = append(grants, derivedPermissionGrants(resource, systemDerivedPermissions(enabled))...)
+		grants = append(grants, derivedPermissionGrants(
+			resource,
+			fieldDerivedPermissions(resourceFieldPermissions(resource)),
+		)...)
+	}
+
 	assignments, nextUserToken, ratelimitData, err := o.client.GetPermissionSetAssignments(
 		ctx,
 		resource.Id.Resource,
@@ -207,6 +329,9 @@ func (o *permissionBuilder) Grant(
 	entitlement *v2.Entitlement,
 ) (annotations.Annotations, error) {
 	logger := ctxzap.Extract(ctx)
+	if isDerivedPermissionEntitlement(entitlement) {
+		return nil, fmt.Errorf("salesforce-connector: object, system, and field permissions come from the permission set and can't be granted directly")
+	}
 	if principal.Id.ResourceType != resourceTypeUser.Id {
 		logger.Warn(
 			"salesforce-connector: only users can be granted permission sets",
@@ -222,13 +347,23 @@ func (o *permissionBuilder) Grant(
 		entitlement.Resource.Id.Resource,
 	)
 	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
-	return outputAnnotations, err
+	if err != nil {
+		if errors.Is(err, client.ErrObjectAlreadyExists) {
+			outputAnnotations.Append(&v2.GrantAlreadyExists{})
+			return outputAnnotations, nil
+		}
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
 }
 
 func (o *permissionBuilder) Revoke(
 	ctx context.Context,
 	grant *v2.Grant,
 ) (annotations.Annotations, error) {
+	if isDerivedPermissionEntitlement(grant.Entitlement) {
+		return nil, fmt.Errorf("salesforce-connector: object, system, and field permissions come from the permission set and can't be revoked directly")
+	}
 	if grant.Principal.Id.ResourceType != resourceTypeUser.Id {
 		return nil, fmt.Errorf("salesforce-connector: only users can have permission set grants revoked")
 	}
@@ -239,12 +374,27 @@ func (o *permissionBuilder) Revoke(
 		grant.Entitlement.Resource.Id.Resource,
 	)
 	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
-	return outputAnnotations, err
+	if err != nil {
+		if errors.Is(err, client.ErrObjectNotFound) {
+			outputAnnotations.Append(&v2.GrantAlreadyRevoked{})
+			return outputAnnotations, nil
+		}
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
 }
 
-func newPermissionBuilder(client *client.SalesforceClient) *permissionBuilder {
+func newPermissionBuilder(
+	client *client.SalesforceClient,
+	syncObjectPermissions bool,
+	systemPermissions []string,
+	fieldPermissions []string,
+) *permissionBuilder {
 	return &permissionBuilder{
-		resourceType: resourceTypePermissionSet,
-		client:       client,
+		resourceType:          resourceTypePermissionSet,
+		client:                client,
+		syncObjectPermissions: syncObjectPermissions,
+		systemPermissions:     systemPermissions,
+		fieldPermissions:      fieldPermissions,
 	}
 }
@@ -28,10 +28,10 @@ func TestPermissionsGrantsPhase2PSGComponents(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	c := newPermissionBuilder(salesforceClient)
+	c := newPermissionBuilder(salesforceClient, false, nil, nil)
 
 	t.Run("should return PSG component grants with GrantExpandable annotation in phase 2", func(t *testing.T) {
-		permission, _ := permissionResource(&client.SalesforcePermission{ID: "PS2X"})
+		permission, _ := permissionResource(&client.SalesforcePermission{ID: "PS2X"}, nil)
 
 		grants := make([]*v2.Grant, 0)
 		pToken := pagination.Token{Token: "", Size: 100}
@@ -61,6 +61,219 @@ func TestPermissionsGrantsPhase2PSGComponents(t *testing.T) {
 	})
 }
 
+// newDerivedPermissionsTestBuilder returns a permission set builder over the
+// fixtures that syncs the given derived permissions.
+func newDerivedPermissionsTestBuilder(
+	ctx context.Context,
+	t *testing.T,
+	syncObjectPermissions bool,
+	systemPermissions []string,
+	fieldPermissions []string,
+) *permissionBuilder {
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		server.Close()
+		test.TearDownDB(ctx, db)
+	})
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newPermissionBuilder(salesforceClient, syncObjectPermissions, systemPermissions, fieldPermissions)
+}
+
+// derivedGrants returns the grants of the given kind across every page.
+func derivedGrants(
+	ctx context.Context,
+	t *testing.T,
+	c *permissionBuilder,
+	resource *v2.Resource,
+	kind derivedPermissionKind,
+) []*v2.Grant {
+	grants := make([]*v2.Grant, 0)
+	pToken := pagination.Token{Token: "", Size: 100}
+	for {
+		nextGrants, results, err := c.Grants(ctx, resource, rs.SyncOpAttrs{PageToken: pToken})
+		require.Nil(t, err)
+		require.NotNil(t, results)
+		test.AssertNoRatelimitAnnotations(t, results.Annotations)
+		for _, g := range nextGrants {
+			if kind.describes(g.Entitlement) {
+				grants = append(grants, g)
+			}
+		}
+		if results.NextPageToken == "" {
+			return grants
+		}
+		pToken.Token = results.NextPageToken
+	}
+}
+
+func TestPermissionsObjectPermissions(t *testing.T) {
+	ctx := context.Background()
+	c := newDerivedPermissionsTestBuilder(ctx, t, true, nil, nil)
+	permission, _ := permissionResource(&client.SalesforcePermission{ID: "345X", Type: "type", Name: "name"}, nil)
+
+	t.Run("should add an entitlement per object access level", func(t *testing.T) {
+		entitlements := make([]*v2.Entitlement, 0)
+		pToken := pagination.Token{Token: "", Size: 1}
+		for {
+			nextEntitlements, results, err := c.Entitlements(ctx, permission, rs.SyncOpAttrs{PageToken: pToken})
+			require.Nil(t, err)
+			require.NotNil(t, results)
+			test.AssertNoRatelimitAnnotations(t, results.Annotations)
+			entitlements = append(entitlements, nextEntitlements...)
+			if results.NextPageToken == "" {
+				break
+			}
+			pToken.Token = results.NextPageToken
+		}
+
+		ids := make([]string, 0, len(entitlements))
+		for _, e := range entitlements {
+			ids = append(ids, e.Id)
+		}
+		require.Equal(t, []string{
+			"permission:345X:assigned",
+			"permission:345X:object:Account:read",
+			"permission:345X:object:Opportunity:read",
+			"permission:345X:object:Opportunity:create",
+			"permission:345X:object:Opportunity:edit",
+			"permission:345X:object:Opportunity:delete",
+			"permission:345X:object:Opportunity:view_all",
+		}, ids)
+	})
+
+	t.Run("should grant object access to the permission set", func(t *testing.T) {
+		grants := derivedGrants(ctx, t, c, permission, objectPermissionKind)
+		require.Len(t, grants, 6)
+		require.Equal(t, "345X", grants[0].Principal.Id.Resource)
+	})
+
+	t.Run("should not grant object access directly", func(t *testing.T) {
+		user, _ := userResource(ctx, &client.SalesforceUser{ID: "0052X"}, nil, false)
+		_, err := c.Grant(ctx, user, &v2.Entitlement{
+			Id:       "permission:345X:object:Account:read",
+			Resource: permission,
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestPermissionsSystemPermissions(t *testing.T) {
+	ctx := context.Background()
+	systemPermissions, err := parseSystemPermissions([]string{"ModifyAllData", " PermissionsViewAllData", "ModifyAllData"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"ModifyAllData", "ViewAllData"}, systemPermissions)
+	c := newDerivedPermissionsTestBuilder(ctx, t, false, systemPermissions, nil)
+
+	t.Run("should list the high-risk system permissions on the resource", func(t *testing.T) {
+		resources, _, err := c.List(ctx, nil, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Len(t, resources, 2)
+
+		descriptions := make(map[string]string)
+		for _, resource := range resources {
+			descriptions[resource.Id.Resource] = resource.Description
+		}
+		require.Equal(t, "High-risk system permissions: ViewAllData", descriptions["345X"])
+		require.Equal(t, "High-risk system permissions: ModifyAllData, ViewAllData", descriptions["PS2X"])
+	})
+
+	t.Run("should mark the assignment and add read-only entitlements", func(t *testing.T) {
+		permission, _ := permissionResource(&client.SalesforcePermission{
+			ID:                "PS2X",
+			Type:              "type",
+			Name:              "ps2",
+			SystemPermissions: []string{"ModifyAllData", "ViewAllData"},
+		}, nil)
+		require.Equal(t, []string{"ModifyAllData", "ViewAllData"}, resourceSystemPermissions(permission))
+
+		entitlements, _, err := c.Entitlements(ctx, permission, rs.SyncOpAttrs{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, 3)
+		require.Contains(t, entitlements[0].Description, "high-risk system permissions ModifyAllData, ViewAllData")
+		require.Equal(t, "permission:PS2X:system:ModifyAllData", entitlements[1].Id)
+		require.Equal(t, "type - ps2: ModifyAllData (sensitive)", entitlements[1].DisplayName)
+		require.Equal(t, "permission:PS2X:system:ViewAllData", entitlements[2].Id)
+
+		grants := derivedGrants(ctx, t, c, permission, systemPermissionKind)
+		require.Len(t, grants, 2)
+		require.Equal(t, "permission:PS2X:system:ModifyAllData", grants[0].Entitlement.Id)
+
+		_, err = c.Grant(ctx, permission, entitlements[1])
+		require.Error(t, err)
+	})
+
+	t.Run("should not add entitlements for permissions not on the resource", func(t *testing.T) {
+		permission, _ := permissionResource(&client.SalesforcePermission{ID: "PS2X", Type: "type", Name: "ps2"}, nil)
+
+		entitlements, _, err := c.Entitlements(ctx, permission, rs.SyncOpAttrs{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, 1)
+	})
+
+	t.Run("should reject names that aren't identifiers", func(t *testing.T) {
+		_, err := parseSystemPermissions([]string{"ModifyAllData, Id FROM User"})
+		require.Error(t, err)
+	})
+}
+
+func TestPermissionsFieldPermissions(t *testing.T) {
+	ctx := context.Background()
+	fields, err := parseFieldNames([]string{"Contact.SSN__c", " Account.Secret__c "})
+	require.Nil(t, err)
+	c := newDerivedPermissionsTestBuilder(ctx, t, false, nil, fields)
+
+	resources, _, err := c.List(ctx, nil, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+	require.Nil(t, err)
+	permissions := make(map[string]*v2.Resource)
+	for _, resource := range resources {
+		permissions[resource.Id.Resource] = resource
+	}
+
+	t.Run("should record the field access of each permission set when listing", func(t *testing.T) {
+		require.Equal(t, []*client.FieldPermission{
+			{PermissionSetID: "345X", Field: "Contact.SSN__c", Read: true},
+		}, resourceFieldPermissions(permissions["345X"]))
+		require.Equal(t, []*client.FieldPermission{
+			{PermissionSetID: "PS2X", Field: "Contact.SSN__c", Read: true, Edit: true},
+		}, resourceFieldPermissions(permissions["PS2X"]))
+	})
+
+	t.Run("should add entitlements for the configured fields only", func(t *testing.T) {
+		entitlements, _, err := c.Entitlements(ctx, permissions["345X"], rs.SyncOpAttrs{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, 2)
+		require.Equal(t, "permission:345X:field:Contact.SSN__c:read", entitlements[1].Id)
+	})
+
+	t.Run("should grant field access to the permission set", func(t *testing.T) {
+		grants := derivedGrants(ctx, t, c, permissions["PS2X"], fieldPermissionKind)
+		require.Len(t, grants, 2)
+		require.Equal(t, "permission:PS2X:field:Contact.SSN__c:read", grants[0].Entitlement.Id)
+		require.Equal(t, "permission:PS2X:field:Contact.SSN__c:edit", grants[1].Entitlement.Id)
+		require.Equal(t, "PS2X", grants[0].Principal.Id.Resource)
+	})
+
+	t.Run("should not add entitlements for field access not on the resource", func(t *testing.T) {
+		permission, _ := permissionResource(&client.SalesforcePermission{ID: "345X", Type: "type", Name: "name"}, nil)
+
+		entitlements, _, err := c.Entitlements(ctx, permission, rs.SyncOpAttrs{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, 1)
+	})
+
+	t.Run("should reject unqualified field names", func(t *testing.T) {
+		_, err := parseFieldNames([]string{"SSN__c"})
+		require.Error(t, err)
+	})
+}
+
 func TestPermissionsList(t *testing.T) {
 	ctx := context.Background()
 
@@ -75,7 +288,7 @@ func TestPermissionsList(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	c := newPermissionBuilder(salesforceClient)
+	c := newPermissionBuilder(salesforceClient, false, nil, nil)
 
 	t.Run("should get permissions with pagination", func(t *testing.T) {
 		resources := make([]*v2.Resource, 0)
@@ -103,7 +316,7 @@ func TestPermissionsList(t *testing.T) {
 	})
 
 	t.Run("should grant and revoke entitlements", func(t *testing.T) {
-		permission, _ := permissionResource(&client.SalesforcePermission{ID: "345X"})
+		permission, _ := permissionResource(&client.SalesforcePermission{ID: "345X"}, nil)
 		user, _ := userResource(ctx, &client.SalesforceUser{ID: "0052X"}, nil, false)
 
 		entitlement := v2.Entitlement{
@@ -0,0 +1,231 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const queueMemberEntitlementName = "member"
+
+// queueBuilder syncs queues, the Group rows with Type = 'Queue'. Queues share
+// the GroupMember table with public groups, so membership reads and writes go
+// through the group client methods.
+type queueBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.SalesforceClient
+}
+
+// queueResource converts a queue into a Resource. sobjectTypes lists the
+// objects (Case, Lead, ...) the queue can own records of.
+func queueResource(queue *client.SalesforceGroup, sobjectTypes []string) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"developer_name": queue.DeveloperName,
+		"sobject_types":  strings.Join(sobjectTypes, ","),
+	}
+
+	options := make([]rs.ResourceOption, 0)
+	if len(sobjectTypes) > 0 {
+		options = append(options, rs.WithDescription(
+			fmt.Sprintf("Owns %s records", strings.Join(sobjectTypes, ", ")),
+		))
+	}
+
+	return rs.NewGroupResource(
+		queue.Name,
+		resourceTypeQueue,
+		queue.ID,
+		[]rs.GroupTraitOption{rs.WithGroupProfile(profile)},
+		options...,
+	)
+}
+
+func (o *queueBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeQueue
+}
+
+func (o *queueBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	attrs rs.SyncOpAttrs,
+) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	token := &attrs.PageToken
+	queues, nextToken, ratelimitData, err := o.client.GetQueues(
+		ctx,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	queueIDs := make([]string, 0, len(queues))
+	for _, queue := range queues {
+		queueIDs = append(queueIDs, queue.ID)
+	}
+	sobjectTypes, ratelimitData, err := o.client.GetQueueSobjectTypes(ctx, queueIDs)
+	outputAnnotations = client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	rv := make([]*v2.Resource, 0)
+	for _, queue := range queues {
+		newResource, err := queueResource(queue, sobjectTypes[queue.ID])
+		if err != nil {
+			return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+		}
+
+		rv = append(rv, newResource)
+	}
+	return rv, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func (o *queueBuilder) Entitlements(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ rs.SyncOpAttrs,
+) (
+	[]*v2.Entitlement,
+	*rs.SyncOpResults,
+	error,
+) {
+	entitlements := []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			queueMemberEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeUser),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s Queue Member", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Member of %s Salesforce queue", resource.DisplayName),
+			),
+		),
+	}
+
+	return entitlements, nil, nil
+}
+
+func (o *queueBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	attrs rs.SyncOpAttrs,
+) (
+	[]*v2.Grant,
+	*rs.SyncOpResults,
+	error,
+) {
+	token := &attrs.PageToken
+	memberships, nextToken, ratelimitData, err := o.client.GetGroupMemberships(
+		ctx,
+		resource.Id.Resource,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, membership := range memberships {
+		// Queue members can be users, public groups, or role groups.
+		var resourceType *v2.ResourceType
+		if membership.IsGroup {
+			resourceType = resourceTypeGroup
+		} else {
+			resourceType = resourceTypeUser
+		}
+
+		grants = append(grants, grant.NewGrant(
+			resource,
+			queueMemberEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceType.Id,
+				Resource:     membership.PrincipalID,
+			},
+		))
+	}
+
+	return grants, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func (o *queueBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+) (annotations.Annotations, error) {
+	logger := ctxzap.Extract(ctx)
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		logger.Warn(
+			"salesforce-connector: only users can be granted queue membership",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("salesforce-connector: only users can be granted queue membership")
+	}
+
+	ratelimitData, err := o.client.AddUserToGroup(
+		ctx,
+		principal.Id.Resource,
+		entitlement.Resource.Id.Resource,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		if errors.Is(err, client.ErrObjectAlreadyExists) {
+			outputAnnotations.Append(&v2.GrantAlreadyExists{})
+			return outputAnnotations, nil
+		}
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
+}
+
+func (o *queueBuilder) Revoke(
+	ctx context.Context,
+	grant *v2.Grant,
+) (annotations.Annotations, error) {
+	if grant.Principal.Id.ResourceType != resourceTypeUser.Id {
+		return nil, fmt.Errorf("salesforce-connector: only users can have queue membership revoked")
+	}
+
+	wasRevoked, ratelimitData, err := o.client.RemoveUserFromGroup(
+		ctx,
+		grant.Principal.Id.Resource,
+		grant.Entitlement.Resource.Id.Resource,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return outputAnnotations, err
+	}
+
+	if !wasRevoked {
+		outputAnnotations.Append(&v2.GrantAlreadyRevoked{})
+	}
+	return outputAnnotations, nil
+}
+
+func newQueueBuilder(client *client.SalesforceClient) *queueBuilder {
+	return &queueBuilder{
+		resourceType: resourceTypeQueue,
+		client:       client,
+	}
+}
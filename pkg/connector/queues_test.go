@@ -0,0 +1,92 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	"github.com/conductorone/baton-salesforce/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueuesList(t *testing.T) {
+	ctx := context.Background()
+
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newQueueBuilder(salesforceClient)
+
+	t.Run("should get queues with the object types they handle", func(t *testing.T) {
+		resources, results, err := c.List(ctx, nil, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.NotNil(t, results)
+		test.AssertNoRatelimitAnnotations(t, results.Annotations)
+		require.Empty(t, results.NextPageToken)
+
+		require.Len(t, resources, 1)
+		require.Equal(t, resourceTypeQueue.Id, resources[0].Id.ResourceType)
+		require.Equal(t, "00G3X", resources[0].Id.Resource)
+		require.Equal(t, "Support Queue", resources[0].DisplayName)
+		require.Equal(t, "Owns Case, Lead records", resources[0].Description)
+
+		trait, err := rs.GetGroupTrait(resources[0])
+		require.Nil(t, err)
+		sobjectTypes, ok := rs.GetProfileStringValue(trait.Profile, "sobject_types")
+		require.True(t, ok)
+		require.Equal(t, "Case,Lead", sobjectTypes)
+	})
+
+	t.Run("should grant and revoke queue membership", func(t *testing.T) {
+		queue, err := queueResource(&client.SalesforceGroup{ID: "00G3X", Name: "Support Queue"}, nil)
+		require.Nil(t, err)
+		user, _ := userResource(ctx, &client.SalesforceUser{ID: "0053X"}, nil, false)
+
+		ent := v2.Entitlement{
+			Id:       entitlement.NewEntitlementID(queue, queueMemberEntitlementName),
+			Resource: queue,
+		}
+
+		grantAnnotations, err := c.Grant(ctx, user, &ent)
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, grantAnnotations)
+
+		grantsBefore, results, err := c.Grants(ctx, queue, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Empty(t, results.NextPageToken)
+		require.Len(t, grantsBefore, 2)
+
+		revokeAnnotations, err := c.Revoke(ctx, &v2.Grant{Entitlement: &ent, Principal: user})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, revokeAnnotations)
+
+		if err := uhttp.ClearCaches(ctx); err != nil {
+			t.Fatal(err)
+		}
+		grantsAfter, _, err := c.Grants(ctx, queue, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Len(t, grantsAfter, 1)
+		require.Equal(t, "0052X", grantsAfter[0].Principal.Id.Resource)
+	})
+
+	t.Run("should only grant queue membership to users", func(t *testing.T) {
+		queue, _ := queueResource(&client.SalesforceGroup{ID: "00G3X"}, nil)
+		group, _ := groupResource(&client.SalesforceGroup{ID: "00G1X"})
+
+		_, err := c.Grant(ctx, group, &v2.Entitlement{Resource: queue})
+		require.Error(t, err)
+	})
+}
@@ -21,6 +21,14 @@ var (
 			v2.ResourceType_TRAIT_GROUP,
 		},
 	}
+	resourceTypeQueue = &v2.ResourceType{
+		Id:          "queue",
+		DisplayName: "Queue",
+		Description: "Groups of type Queue. Only synced when sync-queues is enabled; otherwise queues are synced as groups.",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_GROUP,
+		},
+	}
 	resourceTypePermissionSet = &v2.ResourceType{
 		Id:          "permission",
 		DisplayName: "Permission Set",
@@ -65,6 +73,42 @@ var (
 		Description: "Requires Enterprise Territory Management 2.0 to be enabled in Salesforce.",
 		Annotations: annotations.New(&v2.SkipEntitlements{}, &v2.OptInRequired{}),
 	}
+	resourceTypePermissionSetLicense = &v2.ResourceType{
+		Id:          "permission_set_license",
+		DisplayName: "Permission Set License",
+		Description: "Licenses for paid add-on features (PermissionSetLicense), with seat counts.",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_LICENSE_PROFILE,
+		},
+		Annotations: annotations.New(&v2.OptInRequired{}),
+	}
+	resourceTypeUserLicense = &v2.ResourceType{
+		Id:          "user_license",
+		DisplayName: "User License",
+		Description: "User licenses (UserLicense) with seat counts. Users hold the license of their profile.",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_LICENSE_PROFILE,
+		},
+		Annotations: annotations.New(&v2.OptInRequired{}),
+	}
+	resourceTypeFeatureLicense = &v2.ResourceType{
+		Id:          "feature_license",
+		DisplayName: "Feature License",
+		Description: "Feature licenses turned on per user with a checkbox, such as Marketing User and Knowledge User.",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_LICENSE_PROFILE,
+		},
+		Annotations: annotations.New(&v2.OptInRequired{}),
+	}
+	resourceTypePackageLicense = &v2.ResourceType{
+		Id:          "package_license",
+		DisplayName: "Package License",
+		Description: "Seat licenses of installed managed packages (PackageLicense), with seat counts.",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_LICENSE_PROFILE,
+		},
+		Annotations: annotations.New(&v2.OptInRequired{}),
+	}
 	resourceTypeAgent = &v2.ResourceType{
 		Id:          "agent",
 		DisplayName: "Agent",
@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/conductorone/baton-salesforce/pkg/connector/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -15,21 +16,37 @@ import (
 )
 
 const (
-	roleAssignmentEntitlementName = "assigned"
+	roleAssignmentEntitlementName        = "assigned"
+	roleSubordinateAccessEntitlementName = "subordinate_access"
 )
 
+// roleBuilder syncs roles. Roles point at their parent role, mirroring the role
+// hierarchy. With hierarchyAccess set, each role also gets a "subordinate
+// access" entitlement: users see records owned by users in roles below their
+// own, so the parent role is granted its child's subordinate access, and the
+// grant expands to the parent's own users and to the roles above it.
 type roleBuilder struct {
-	resourceType *v2.ResourceType
-	client       *client.SalesforceClient
+	resourceType    *v2.ResourceType
+	client          *client.SalesforceClient
+	hierarchyAccess bool
 }
 
 // roleResource convert a SalesforceRole into a Resource.
 func roleResource(role *client.SalesforceRole) (*v2.Resource, error) {
+	var opts []rs.ResourceOption
+	if role.ParentRoleID != "" {
+		opts = append(opts, rs.WithParentResourceID(&v2.ResourceId{
+			ResourceType: resourceTypeRole.Id,
+			Resource:     role.ParentRoleID,
+		}))
+	}
+
 	newRoleResource, err := rs.NewRoleResource(
 		role.Name,
 		resourceTypeRole,
 		role.ID,
 		[]rs.RoleTraitOption{},
+		opts...,
 	)
 	if err != nil {
 		return nil, err
@@ -105,6 +122,20 @@ func (o *roleBuilder) Entitlements(
 			),
 		),
 	}
+	if o.hierarchyAccess {
+		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(
+			resource,
+			roleSubordinateAccessEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeUser, resourceTypeRole),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s Subordinate Access", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Sees records owned by users in the %s role through the role hierarchy", resource.DisplayName),
+			),
+			entitlement.WithAnnotation(&v2.EntitlementImmutable{}),
+		))
+	}
 
 	return entitlements, nil, nil
 }
@@ -136,6 +167,11 @@ func (o *roleBuilder) Grants(
 	}
 
 	grants := make([]*v2.Grant, 0)
+	// The hierarchy grant only depends on the role itself, so it goes out with
+	// the first page of assignments.
+	if o.hierarchyAccess && token.Token == "" && resource.ParentResourceId != nil {
+		grants = append(grants, subordinateAccessGrant(resource, resource.ParentResourceId.Resource))
+	}
 	for _, assignment := range assignments {
 		grants = append(grants, grant.NewGrant(
 			resource,
@@ -152,12 +188,46 @@ func (o *roleBuilder) Grants(
 	}, nil
 }
 
+// subordinateAccessGrant grants the parent role access to the role's
+// subordinate records. It expands to the users assigned the parent role and,
+// through the parent's own subordinate access, to every role further up.
+func subordinateAccessGrant(resource *v2.Resource, parentRoleID string) *v2.Grant {
+	parentEntitlementID := func(name string) string {
+		return fmt.Sprintf("%s:%s:%s", resourceTypeRole.Id, parentRoleID, name)
+	}
+	return grant.NewGrant(
+		resource,
+		roleSubordinateAccessEntitlementName,
+		&v2.ResourceId{
+			ResourceType: resourceTypeRole.Id,
+			Resource:     parentRoleID,
+		},
+		grant.WithAnnotation(&v2.GrantExpandable{
+			EntitlementIds: []string{
+				parentEntitlementID(roleAssignmentEntitlementName),
+				parentEntitlementID(roleSubordinateAccessEntitlementName),
+			},
+		}),
+		grant.WithAnnotation(&v2.GrantImmutable{}),
+	)
+}
+
+// isSubordinateAccessEntitlement reports whether the entitlement is a role's
+// subordinate access, which follows the role hierarchy and can't be granted
+// or revoked directly.
+func isSubordinateAccessEntitlement(entitlement *v2.Entitlement) bool {
+	return strings.HasSuffix(entitlement.Id, ":"+roleSubordinateAccessEntitlementName)
+}
+
 func (o *roleBuilder) Grant(
 	ctx context.Context,
 	principal *v2.Resource,
 	entitlement *v2.Entitlement,
 ) (annotations.Annotations, error) {
 	logger := ctxzap.Extract(ctx)
+	if isSubordinateAccessEntitlement(entitlement) {
+		return nil, fmt.Errorf("salesforce-connector: subordinate access follows the role hierarchy and can't be granted directly")
+	}
 	if principal.Id.ResourceType != resourceTypeUser.Id {
 		logger.Warn(
 			"salesforce-connector: only users can be granted roles",
@@ -180,6 +250,9 @@ func (o *roleBuilder) Revoke(
 	ctx context.Context,
 	grant *v2.Grant,
 ) (annotations.Annotations, error) {
+	if isSubordinateAccessEntitlement(grant.Entitlement) {
+		return nil, fmt.Errorf("salesforce-connector: subordinate access follows the role hierarchy and can't be revoked directly")
+	}
 	ratelimitData, err := o.client.RemoveUserFromRole(
 		ctx,
 		grant.Principal.Id.Resource,
@@ -189,9 +262,10 @@ func (o *roleBuilder) Revoke(
 	return outputAnnotations, err
 }
 
-func newRoleBuilder(client *client.SalesforceClient) *roleBuilder {
+func newRoleBuilder(client *client.SalesforceClient, hierarchyAccess bool) *roleBuilder {
 	return &roleBuilder{
-		resourceType: resourceTypeRole,
-		client:       client,
+		resourceType:    resourceTypeRole,
+		client:          client,
+		hierarchyAccess: hierarchyAccess,
 	}
 }
@@ -28,7 +28,7 @@ func TestRolesList(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	c := newRoleBuilder(salesforceClient)
+	c := newRoleBuilder(salesforceClient, false)
 
 	t.Run("should get roles with pagination", func(t *testing.T) {
 		resources := make([]*v2.Resource, 0)
@@ -106,4 +106,50 @@ func TestRolesList(t *testing.T) {
 		require.Equal(t, "", results.NextPageToken)
 		require.Len(t, grantsAfter, 0)
 	})
+
+	t.Run("should link roles to their parent role", func(t *testing.T) {
+		resources, _, err := c.List(ctx, nil, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+
+		parents := make(map[string]*v2.ResourceId)
+		for _, resource := range resources {
+			parents[resource.Id.Resource] = resource.ParentResourceId
+		}
+		require.Nil(t, parents["199X"])
+		require.NotNil(t, parents["299X"])
+		require.Equal(t, resourceTypeRole.Id, parents["299X"].ResourceType)
+		require.Equal(t, "199X", parents["299X"].Resource)
+	})
+
+	t.Run("should grant subordinate access to the parent role", func(t *testing.T) {
+		hc := newRoleBuilder(salesforceClient, true)
+		role, _ := roleResource(&client.SalesforceRole{ID: "299X", Name: "name", ParentRoleID: "199X"})
+
+		entitlements, _, err := hc.Entitlements(ctx, role, rs.SyncOpAttrs{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, 2)
+		require.Equal(t, "role:299X:subordinate_access", entitlements[1].Id)
+
+		grants, _, err := hc.Grants(ctx, role, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Len(t, grants, 1)
+		require.Equal(t, "role:299X:subordinate_access", grants[0].Entitlement.Id)
+		require.Equal(t, resourceTypeRole.Id, grants[0].Principal.Id.ResourceType)
+		require.Equal(t, "199X", grants[0].Principal.Id.Resource)
+
+		var expandable v2.GrantExpandable
+		found, err := test.UnmarshalFromAnys(&expandable, grants[0].Annotations)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, []string{"role:199X:assigned", "role:199X:subordinate_access"}, expandable.EntitlementIds)
+
+		topRole, _ := roleResource(&client.SalesforceRole{ID: "199X", Name: "name"})
+		grants, _, err = hc.Grants(ctx, topRole, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Len(t, grants, 0)
+
+		user, _ := userResource(ctx, &client.SalesforceUser{ID: "0052X"}, nil, false)
+		_, err = hc.Grant(ctx, user, entitlements[1])
+		require.Error(t, err)
+	})
 }
@@ -0,0 +1,99 @@
+package connector
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const systemPermissionsProfileKey = "system_permissions"
+
+var systemPermissionNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// sensitiveSystemPermissions are the system permissions that amount to admin
+// access: seeing or changing every record, managing users and their access,
+// or changing the org's code and configuration. Their entitlements are marked
+// as sensitive.
+var sensitiveSystemPermissions = []string{
+	"ModifyAllData",
+	"ViewAllData",
+	"ManageUsers",
+	"ManageInternalUsers",
+	"ManageProfilesPermissionsets",
+	"AssignPermissionSets",
+	"ResetPasswords",
+	"AuthorApex",
+	"CustomizeApplication",
+	"ModifyMetadata",
+}
+
+// parseSystemPermissions normalizes the configured high-risk system
+// permissions to their API names without the "Permissions" prefix, so both
+// ModifyAllData and PermissionsModifyAllData are accepted. The names end up in
+// SOQL field lists, so anything that isn't a plain identifier is rejected.
+func parseSystemPermissions(names []string) ([]string, error) {
+	rv := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "Permissions")
+		if name == "" {
+			continue
+		}
+		if !systemPermissionNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("baton-salesforce: invalid system permission name %q", name)
+		}
+		if !slices.Contains(rv, name) {
+			rv = append(rv, name)
+		}
+	}
+	return rv, nil
+}
+
+// systemPermissionsProfile records the high-risk system permissions a
+// permission set turns on in its resource profile, so that its entitlements
+// and grants don't need to query the permission set again.
+func systemPermissionsProfile(enabled []string) map[string]interface{} {
+	values := make([]interface{}, 0, len(enabled))
+	for _, name := range enabled {
+		values = append(values, name)
+	}
+	return map[string]interface{}{systemPermissionsProfileKey: values}
+}
+
+// resourceSystemPermissions reads back the system permissions recorded by
+// systemPermissionsProfile.
+func resourceSystemPermissions(resource *v2.Resource) []string {
+	profile := rs.GetProfile(resource)
+	if profile == nil {
+		return nil
+	}
+	values := profile.GetFields()[systemPermissionsProfileKey].GetListValue().GetValues()
+	enabled := make([]string, 0, len(values))
+	for _, value := range values {
+		enabled = append(enabled, value.GetStringValue())
+	}
+	return enabled
+}
+
+// systemDerivedPermissions returns the high-risk system permissions the
+// permission set turns on, e.g. "system:ModifyAllData".
+func systemDerivedPermissions(enabled []string) []derivedPermission {
+	derived := make([]derivedPermission, 0, len(enabled))
+	for _, name := range enabled {
+		permission := derivedPermission{
+			kind:        systemPermissionKind,
+			name:        name,
+			displayName: name,
+			description: fmt.Sprintf("High-risk system permission %s", name),
+		}
+		if slices.Contains(sensitiveSystemPermissions, name) {
+			permission.displayName = fmt.Sprintf("%s (sensitive)", name)
+			permission.description = fmt.Sprintf("Sensitive system permission %s, equivalent to admin access", name)
+		}
+		derived = append(derived, permission)
+	}
+	return derived
+}
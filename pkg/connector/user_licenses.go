@@ -0,0 +1,161 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const userLicenseAssignmentEntitlementName = "assigned"
+
+// userLicenseBuilder syncs user licenses along with who holds one. A user's
+// license comes from their profile, so the grants are read-only here; change
+// the user's profile to move them to another license.
+type userLicenseBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.SalesforceClient
+}
+
+func userLicenseResource(license *client.SalesforceUserLicense) (*v2.Resource, error) {
+	assignedEntitlementID := fmt.Sprintf("%s:%s:%s",
+		resourceTypeUserLicense.Id,
+		license.ID,
+		userLicenseAssignmentEntitlementName,
+	)
+	description := fmt.Sprintf("%d of %d licenses used", license.UsedLicenses, license.TotalLicenses)
+	if license.Status != "" {
+		description = fmt.Sprintf("%s (%s)", description, license.Status)
+	}
+	if license.LicenseDefinitionKey != "" {
+		description = fmt.Sprintf("%s: %s", license.LicenseDefinitionKey, description)
+	}
+
+	return rs.NewResource(
+		license.Name,
+		resourceTypeUserLicense,
+		license.ID,
+		rs.WithDescription(description),
+		rs.WithLicenseProfileTrait(
+			rs.WithLicenseName(license.Name),
+			rs.WithLicenseSeats(license.TotalLicenses, license.UsedLicenses),
+			rs.WithLicenseEntitlementIDs(assignedEntitlementID),
+		),
+	)
+}
+
+func (o *userLicenseBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeUserLicense
+}
+
+func (o *userLicenseBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	attrs rs.SyncOpAttrs,
+) (
+	[]*v2.Resource,
+	*rs.SyncOpResults,
+	error,
+) {
+	token := &attrs.PageToken
+	licenses, nextToken, ratelimitData, err := o.client.GetUserLicenses(
+		ctx,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	rv := make([]*v2.Resource, 0)
+	for _, license := range licenses {
+		newResource, err := userLicenseResource(license)
+		if err != nil {
+			return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+		}
+
+		rv = append(rv, newResource)
+	}
+	return rv, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func (o *userLicenseBuilder) Entitlements(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ rs.SyncOpAttrs,
+) (
+	[]*v2.Entitlement,
+	*rs.SyncOpResults,
+	error,
+) {
+	entitlements := []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			userLicenseAssignmentEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeUser),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s User License", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Holds the %s user license through their profile in Salesforce", resource.DisplayName),
+			),
+			entitlement.WithAnnotation(&v2.EntitlementImmutable{}),
+		),
+	}
+
+	return entitlements, nil, nil
+}
+
+func (o *userLicenseBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	attrs rs.SyncOpAttrs,
+) (
+	[]*v2.Grant,
+	*rs.SyncOpResults,
+	error,
+) {
+	token := &attrs.PageToken
+	users, nextToken, ratelimitData, err := o.client.GetUserLicenseHolders(
+		ctx,
+		resource.Id.Resource,
+		token.Token,
+		token.Size,
+	)
+	outputAnnotations := client.WithRateLimitAnnotations(ratelimitData)
+	if err != nil {
+		return nil, &rs.SyncOpResults{Annotations: outputAnnotations}, err
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, user := range users {
+		grants = append(grants, grant.NewGrant(
+			resource,
+			userLicenseAssignmentEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceTypeUser.Id,
+				Resource:     user.ID,
+			},
+			grant.WithAnnotation(&v2.GrantImmutable{}),
+		))
+	}
+	return grants, &rs.SyncOpResults{
+		NextPageToken: nextToken,
+		Annotations:   outputAnnotations,
+	}, nil
+}
+
+func newUserLicenseBuilder(client *client.SalesforceClient) *userLicenseBuilder {
+	return &userLicenseBuilder{
+		resourceType: resourceTypeUserLicense,
+		client:       client,
+	}
+}
@@ -0,0 +1,78 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-salesforce/test"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserLicenses(t *testing.T) {
+	ctx := context.Background()
+
+	server, db, err := test.FixturesServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDownDB(ctx, db)
+	defer server.Close()
+
+	// Profile 198X consumes the Salesforce user license. 0052X has it too but
+	// is deactivated, so doesn't hold a seat.
+	_, err = db.ExecContext(
+		ctx,
+		`UPDATE User SET "profileid" = '198X' WHERE Id = '0051X'`,
+	)
+	require.NoError(t, err)
+	_, err = db.ExecContext(
+		ctx,
+		`UPDATE User SET "profileid" = '198X', "isactive" = 0 WHERE Id = '0052X'`,
+	)
+	require.NoError(t, err)
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newUserLicenseBuilder(salesforceClient)
+
+	t.Run("should list licenses with seat counts", func(t *testing.T) {
+		resources, results, err := c.List(ctx, nil, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.NotNil(t, results)
+		test.AssertNoRatelimitAnnotations(t, results.Annotations)
+		require.Empty(t, results.NextPageToken)
+
+		require.Len(t, resources, 2)
+		require.Equal(t, "100X", resources[0].Id.Resource)
+		require.Equal(t, "Salesforce", resources[0].DisplayName)
+		require.Equal(t, "SFDC: 1 of 100 licenses used (Active)", resources[0].Description)
+
+		trait, err := rs.GetLicenseProfileTrait(resources[0])
+		require.Nil(t, err)
+		require.Equal(t, int64(100), trait.GetPurchasedSeats())
+		require.Equal(t, int64(1), trait.GetConsumedSeats())
+		require.Equal(t, []string{"user_license:100X:assigned"}, trait.GetEntitlementIds())
+	})
+
+	t.Run("should grant licenses to users through their profile", func(t *testing.T) {
+		resources, _, err := c.List(ctx, nil, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Len(t, resources, 2)
+
+		grants, results, err := c.Grants(ctx, resources[0], rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Empty(t, results.NextPageToken)
+		require.Len(t, grants, 1)
+		require.Equal(t, resourceTypeUser.Id, grants[0].Principal.Id.ResourceType)
+		require.Equal(t, "0051X", grants[0].Principal.Id.Resource)
+		require.Equal(t, "user_license:100X:assigned", grants[0].Entitlement.Id)
+
+		grants, _, err = c.Grants(ctx, resources[1], rs.SyncOpAttrs{PageToken: pagination.Token{Size: 100}})
+		require.Nil(t, err)
+		require.Empty(t, grants)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/crypto"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
@@ -23,7 +24,10 @@ type userBuilder struct {
 	syncNonStandardUsers      bool
 }
 
-var _ connectorbuilder.AccountManagerV2 = &userBuilder{}
+var (
+	_ connectorbuilder.AccountManagerV2         = &userBuilder{}
+	_ connectorbuilder.CredentialManagerLimited = &userBuilder{}
+)
 
 const (
 	userTypeAutomatedProcess = "AutomatedProcess"
@@ -89,6 +93,9 @@ func userResource(
 		"email":        email,
 		"id":           user.ID,
 	}
+	if userLogin != nil {
+		profile["is_password_locked"] = userLogin.IsPasswordLocked
+	}
 
 	userTraitOptions := []rs.UserTraitOption{
 		rs.WithUserProfile(profile),
@@ -368,6 +375,64 @@ func (o *userBuilder) CreateAccountCapabilityDetails(ctx context.Context) (*v2.C
 	}, nil, nil
 }
 
+// Rotate rotates a user's password. With no password requested, Salesforce
+// emails the user a link to reset it themselves. Otherwise the generated
+// password is set on the user and returned so it can be handed over.
+func (o *userBuilder) Rotate(
+	ctx context.Context,
+	resourceId *v2.ResourceId,
+	credentialOptions *v2.LocalCredentialOptions,
+) (
+	[]*v2.PlaintextData,
+	annotations.Annotations,
+	error,
+) {
+	l := ctxzap.Extract(ctx)
+
+	if resourceId.ResourceType != resourceTypeUser.Id {
+		return nil, nil, fmt.Errorf("baton-salesforce: only users can have credentials rotated")
+	}
+	userId := resourceId.Resource
+
+	if credentialOptions.GetNoPassword() != nil {
+		l.Info("Sending reset password email", zap.String("user_id", userId))
+		err := o.client.SendResetPasswordEmail(ctx, userId)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, nil
+	}
+
+	password, err := crypto.GeneratePassword(ctx, credentialOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-salesforce: cannot generate password: %w", err)
+	}
+
+	err = o.client.SetPassword(ctx, userId, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	l.Debug("Password set", zap.String("user_id", userId))
+
+	return []*v2.PlaintextData{
+		{
+			Name:        "password",
+			Description: "The user's new Salesforce password",
+			Bytes:       []byte(password),
+		},
+	}, nil, nil
+}
+
+func (o *userBuilder) RotateCapabilityDetails(ctx context.Context) (*v2.CredentialDetailsCredentialRotation, annotations.Annotations, error) {
+	return &v2.CredentialDetailsCredentialRotation{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+	}, nil, nil
+}
+
 func newUserBuilder(
 	client *client.SalesforceClient,
 	shouldUseUsernameForEmail bool,
@@ -2,10 +2,15 @@ package connector
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/conductorone/baton-salesforce/pkg/connector/client"
 	"github.com/conductorone/baton-salesforce/test"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
@@ -53,6 +58,46 @@ func TestUsersList(t *testing.T) {
 		require.Len(t, resources, 3)
 		require.NotEmpty(t, resources[0].Id)
 	})
+
+	t.Run("should get users through a bulk query job above the threshold", func(t *testing.T) {
+		server, db, err := test.FixturesServer(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDownDB(ctx, db)
+		defer server.Close()
+
+		var bulkRequests atomic.Int32
+		bulkServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.Contains(r.URL.Path, "/jobs/query") {
+				bulkRequests.Add(1)
+			}
+			server.Config.Handler.ServeHTTP(w, r)
+		}))
+		defer bulkServer.Close()
+
+		salesforceClient, err := test.Client(ctx, bulkServer.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		salesforceClient.SetBulkQueryThreshold(1)
+		c := newUserBuilder(salesforceClient, false, true, false)
+
+		resources, results, err := c.List(ctx, nil, rs.SyncOpAttrs{PageToken: pagination.Token{Size: 1}})
+		require.NoError(t, err)
+		require.NotNil(t, results)
+		test.AssertNoRatelimitAnnotations(t, results.Annotations)
+		require.Empty(t, results.NextPageToken)
+		require.Len(t, resources, 3)
+
+		ids := make([]string, 0, len(resources))
+		for _, resource := range resources {
+			ids = append(ids, resource.Id.Resource)
+		}
+		require.ElementsMatch(t, []string{"0051X", "0052X", "0053X"}, ids)
+		// create job, poll status, fetch the single results page.
+		require.EqualValues(t, 3, bulkRequests.Load())
+	})
 }
 
 // TestGetBotDefinitionsGracefulSkip verifies that an org without Agentforce or
@@ -124,3 +169,74 @@ func TestAccountTypeForUser(t *testing.T) {
 		})
 	}
 }
+
+// TestUserResourcePasswordLocked checks that locked-out users can be found by
+// their profile.
+func TestUserResourcePasswordLocked(t *testing.T) {
+	ctx := context.Background()
+	user := &client.SalesforceUser{ID: "0051X", IsActive: true}
+
+	resource, err := userResource(ctx, user, &client.UserLogin{IsPasswordLocked: true}, false)
+	require.NoError(t, err)
+	trait, err := rs.GetUserTrait(resource)
+	require.NoError(t, err)
+	require.True(t, trait.GetProfile().GetFields()["is_password_locked"].GetBoolValue())
+	require.Equal(t, v2.UserTrait_Status_STATUS_ENABLED, trait.GetStatus().GetStatus())
+
+	resource, err = userResource(ctx, user, nil, false)
+	require.NoError(t, err)
+	trait, err = rs.GetUserTrait(resource)
+	require.NoError(t, err)
+	require.NotContains(t, trait.GetProfile().GetFields(), "is_password_locked")
+}
+
+// TestUserRotate checks both ways of rotating a password: the reset email
+// when no password is requested, and setting a generated one otherwise.
+func TestUserRotate(t *testing.T) {
+	ctx := context.Background()
+
+	var lastMethod string
+	var lastPath string
+	var lastBody map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastMethod = r.Method
+		lastPath = r.URL.Path
+		lastBody = nil
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &lastBody)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	salesforceClient, err := test.Client(ctx, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newUserBuilder(salesforceClient, false, false, false)
+	userID := &v2.ResourceId{ResourceType: resourceTypeUser.Id, Resource: "0051X"}
+
+	t.Run("should send a reset email without a password", func(t *testing.T) {
+		plaintexts, _, err := c.Rotate(ctx, userID, &v2.LocalCredentialOptions{
+			Options: &v2.LocalCredentialOptions_NoPassword_{NoPassword: &v2.LocalCredentialOptions_NoPassword{}},
+		})
+		require.NoError(t, err)
+		require.Empty(t, plaintexts)
+		require.Equal(t, http.MethodDelete, lastMethod)
+		require.True(t, strings.HasSuffix(lastPath, "/sobjects/User/0051X/password"))
+	})
+
+	t.Run("should set and return a random password", func(t *testing.T) {
+		plaintexts, _, err := c.Rotate(ctx, userID, &v2.LocalCredentialOptions{
+			Options: &v2.LocalCredentialOptions_RandomPassword_{
+				RandomPassword: &v2.LocalCredentialOptions_RandomPassword{Length: 16},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, plaintexts, 1)
+		require.Equal(t, "password", plaintexts[0].Name)
+		require.Len(t, plaintexts[0].Bytes, 16)
+		require.Equal(t, http.MethodPost, lastMethod)
+		require.True(t, strings.HasSuffix(lastPath, "/sobjects/User/0051X/password"))
+		require.Equal(t, string(plaintexts[0].Bytes), lastBody["NewPassword"])
+	})
+}
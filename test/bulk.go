@@ -0,0 +1,147 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/conductorone/simpleforce"
+	"github.com/google/uuid"
+)
+
+// bulkJobs fakes the Bulk API 2.0 query endpoints. Jobs run synchronously
+// against the fixture DB when they're created, so the first status poll
+// already reports JobComplete.
+type bulkJobs struct {
+	mu      sync.Mutex
+	results map[string][]simpleforce.SObject
+}
+
+func newBulkJobs() *bulkJobs {
+	return &bulkJobs{results: make(map[string][]simpleforce.SObject)}
+}
+
+func (b *bulkJobs) handle(
+	ctx context.Context,
+	db *sql.DB,
+	writer http.ResponseWriter,
+	request *http.Request,
+) ([]byte, error) {
+	_, rest, _ := strings.Cut(request.URL.Path, "jobs/query")
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+
+	switch {
+	case request.Method == http.MethodPost && rest == "":
+		body, err := getBody(request)
+		if err != nil {
+			return nil, err
+		}
+		soql, _ := body["query"].(string)
+		rows, err := query(ctx, db, soql)
+		if err != nil {
+			return nil, err
+		}
+		jobID, err := uuid.NewUUID()
+		if err != nil {
+			return nil, err
+		}
+		b.mu.Lock()
+		b.results[jobID.String()] = rows
+		b.mu.Unlock()
+		return json.Marshal(map[string]string{"id": jobID.String(), "state": "UploadComplete"})
+	case request.Method == http.MethodGet && len(parts) == 1:
+		if _, ok := b.job(parts[0]); !ok {
+			return nil, fmt.Errorf("unknown bulk job %s", parts[0])
+		}
+		return json.Marshal(map[string]string{"id": parts[0], "state": "JobComplete"})
+	case request.Method == http.MethodGet && len(parts) == 2 && parts[1] == "results":
+		rows, ok := b.job(parts[0])
+		if !ok {
+			return nil, fmt.Errorf("unknown bulk job %s", parts[0])
+		}
+		return writeBulkResults(writer, request, rows)
+	}
+	return nil, fmt.Errorf("unsupported bulk route: %s %s", request.Method, request.URL.Path)
+}
+
+func (b *bulkJobs) job(id string) ([]simpleforce.SObject, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	rows, ok := b.results[id]
+	return rows, ok
+}
+
+// writeBulkResults renders one page of rows as CSV. The locator is just the
+// offset of the next page, which is enough for the client to treat it as
+// opaque.
+func writeBulkResults(
+	writer http.ResponseWriter,
+	request *http.Request,
+	rows []simpleforce.SObject,
+) ([]byte, error) {
+	offset := 0
+	if locator := request.URL.Query().Get("locator"); locator != "" {
+		var err error
+		offset, err = strconv.Atoi(locator)
+		if err != nil {
+			return nil, err
+		}
+	}
+	end := len(rows)
+	if maxRecords := request.URL.Query().Get("maxRecords"); maxRecords != "" {
+		limit, err := strconv.Atoi(maxRecords)
+		if err != nil {
+			return nil, err
+		}
+		end = min(offset+limit, len(rows))
+	}
+
+	nextLocator := "null"
+	if end < len(rows) {
+		nextLocator = strconv.Itoa(end)
+	}
+	writer.Header().Set("Content-Type", "text/csv")
+	writer.Header().Set("Sforce-Locator", nextLocator)
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if len(rows) == 0 {
+		w.Flush()
+		return buf.Bytes(), w.Error()
+	}
+
+	header := make([]string, 0, len(rows[0]))
+	for column := range rows[0] {
+		header = append(header, column)
+	}
+	slices.Sort(header)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, row := range rows[offset:end] {
+		values := make([]string, 0, len(header))
+		for _, column := range header {
+			switch value := row[column].(type) {
+			case nil:
+				values = append(values, "")
+			case []byte:
+				values = append(values, string(value))
+			default:
+				values = append(values, fmt.Sprint(value))
+			}
+		}
+		if err := w.Write(values); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -1,8 +1,9 @@
 CREATE TABLE GroupMember
 (
-    Id            TEXT PRIMARY KEY,
-    GroupId       TEXT,
-    UserOrGroupId TEXT
+    Id             TEXT PRIMARY KEY,
+    GroupId        TEXT,
+    UserOrGroupId  TEXT,
+    SystemModstamp TEXT DEFAULT ''
 );
 
 CREATE TABLE "Group"
@@ -21,17 +22,20 @@ CREATE TABLE PermissionSetAssignment
     PermissionSetId      TEXT DEFAULT '',
     PermissionSetGroupId TEXT DEFAULT '',
     AssigneeId           TEXT,
-    IsActive             INT DEFAULT 1
+    IsActive             INT DEFAULT 1,
+    SystemModstamp       TEXT DEFAULT ''
 );
 
 CREATE TABLE PermissionSet
 (
-    Id        TEXT PRIMARY KEY,
-    Name      TEXT,
-    Label     TEXT,
-    Type      TEXT,
-    ProfileId TEXT,
-    "Profile" TEXT
+    Id                       TEXT PRIMARY KEY,
+    Name                     TEXT,
+    Label                    TEXT,
+    Type                     TEXT,
+    ProfileId                TEXT,
+    "Profile"                TEXT,
+    PermissionsModifyAllData INT DEFAULT 0,
+    PermissionsViewAllData   INT DEFAULT 0
 );
 
 CREATE TABLE Profile
@@ -43,8 +47,9 @@ CREATE TABLE Profile
 
 CREATE TABLE UserRole
 (
-    Id   TEXT PRIMARY KEY,
-    Name TEXT
+    Id           TEXT PRIMARY KEY,
+    Name         TEXT,
+    ParentRoleId TEXT
 );
 
 CREATE TABLE User
@@ -58,7 +63,15 @@ CREATE TABLE User
     UserType      TEXT,
     ProfileId     TEXT,
     UserRoleId    TEXT,
-    LastLoginDate TEXT
+    LastLoginDate TEXT,
+    UserPermissionsMarketingUser   INT DEFAULT 0,
+    UserPermissionsKnowledgeUser   INT DEFAULT 0,
+    UserPermissionsInteractionUser INT DEFAULT 0,
+    UserPermissionsSupportUser     INT DEFAULT 0,
+    UserPermissionsSFContentUser   INT DEFAULT 0,
+    UserPermissionsOfflineUser     INT DEFAULT 0,
+    UserPermissionsMobileUser      INT DEFAULT 0,
+    UserPermissionsLiveAgentUser   INT DEFAULT 0
 );
 
 CREATE TABLE PermissionSetGroup
@@ -75,8 +88,12 @@ CREATE TABLE PermissionSetGroup
 
 CREATE TABLE UserLicense
 (
-    Id   TEXT PRIMARY KEY,
-    Name TEXT
+    Id                   TEXT PRIMARY KEY,
+    Name                 TEXT,
+    LicenseDefinitionKey TEXT,
+    TotalLicenses        INT,
+    UsedLicenses         INT,
+    Status               TEXT
 );
 
 CREATE TABLE PermissionSetGroupComponent
@@ -144,23 +161,33 @@ VALUES ('00G1X',
         '',
         'AllInternalUsers',
         'Organization',
+        ''),
+       ('00G3X',
+        'Support Queue',
+        '',
+        'Support_Queue',
+        'Queue',
         '');
 
-INSERT INTO GroupMember (Id, GroupId, UserOrGroupId)
-VALUES ('1X', '00G1X', '0051X');
-INSERT INTO PermissionSet (Id, Name, Label, Type, ProfileId, "Profile")
-VALUES ('345X', 'name', 'label', 'type', '1', '{"Name": "profile name"}');
-INSERT INTO PermissionSetAssignment (Id, PermissionSetId, PermissionSetGroupId, AssigneeId, IsActive)
-VALUES ('1X', '345X', '', '0051X', 1),
-       ('PSA1X', '', 'PSG1X', '0051X', 1);
+INSERT INTO GroupMember (Id, GroupId, UserOrGroupId, SystemModstamp)
+VALUES ('1X', '00G1X', '0051X', '2025-03-26T16:43:31.000+0000'),
+       ('2X', '00G3X', '0052X', '2025-03-26T16:50:00.000+0000');
+INSERT INTO PermissionSet (Id, Name, Label, Type, ProfileId, "Profile", PermissionsModifyAllData, PermissionsViewAllData)
+VALUES ('345X', 'name', 'label', 'type', '198X', '{"Name": "profile name"}', 0, 1);
+INSERT INTO PermissionSetAssignment (Id, PermissionSetId, PermissionSetGroupId, AssigneeId, IsActive, SystemModstamp)
+VALUES ('1X', '345X', '', '0051X', 1, '2025-03-26T16:43:31.000+0000'),
+       ('PSA1X', '', 'PSG1X', '0051X', 1, '2025-03-27T09:00:00.000+0000');
+INSERT INTO UserLicense (Id, Name, LicenseDefinitionKey, TotalLicenses, UsedLicenses, Status)
+VALUES ('100X', 'Salesforce', 'SFDC', 100, 1, 'Active'),
+       ('200X', 'Salesforce Platform', 'AUL', 50, 0, 'Active');
 INSERT INTO Profile (Id, Name, UserLicenseId)
-VALUES ('198X', 'name', '1'),
-       ('298X', 'name', '2');
-INSERT INTO UserRole (Id, Name)
-VALUES ('199X', 'name'),
-       ('299X', 'name');
-INSERT INTO PermissionSet (Id, Name, Label, Type, ProfileId, "Profile")
-VALUES ('PS2X', 'ps2', 'PS2 Label', 'type', '', '');
+VALUES ('198X', 'name', '100X'),
+       ('298X', 'name', '200X');
+INSERT INTO UserRole (Id, Name, ParentRoleId)
+VALUES ('199X', 'name', ''),
+       ('299X', 'name', '199X');
+INSERT INTO PermissionSet (Id, Name, Label, Type, ProfileId, "Profile", PermissionsModifyAllData, PermissionsViewAllData)
+VALUES ('PS2X', 'ps2', 'PS2 Label', 'type', '', '', 1, 1);
 INSERT INTO PermissionSetGroup (Id, IsDeleted, DeveloperName, Language, MasterLabel, NamespacePrefix, Description, HasActivationRequired)
 VALUES ('PSG1X', '', 'TestPSG', 'en_US', 'Test PSG', '', 'Test permission set group', '');
 INSERT INTO PermissionSetGroupComponent (Id, IsDeleted, PermissionSetGroupId, PermissionSetId)
@@ -240,4 +267,161 @@ VALUES ('PV1', 'Owner', 1),
 
 INSERT INTO BotDefinition (Id, DeveloperName, MasterLabel, BotUserId)
 VALUES ('0Xx000000000001', 'Service_Agent', 'Service Agent', '0051X'),
-       ('0Xx000000000002', 'Order_Bot', 'Order Bot', '');
\ No newline at end of file
+       ('0Xx000000000002', 'Order_Bot', 'Order Bot', '');
+
+CREATE TABLE SetupAuditTrail
+(
+    Id           TEXT PRIMARY KEY,
+    Action       TEXT,
+    Section      TEXT,
+    Display      TEXT,
+    CreatedDate  TEXT,
+    CreatedById  TEXT,
+    DelegateUser TEXT
+)
+
+INSERT INTO SetupAuditTrail (Id, Action, Section, Display, CreatedDate, CreatedById, DelegateUser)
+VALUES ('0YM1X', 'changedprofileforuser', 'Manage Users', 'Changed profile for user jdoe@example.com from Standard User to name', '2025-03-26T17:00:00.000+0000', '0051X', ''),
+       ('0YM2X', 'createdcf', 'Customize Accounts', 'Created custom field Region (Text)', '2025-03-26T17:05:00.000+0000', '0051X', ''),
+       ('0YM3X', 'PermSetUnassign', 'Manage Users', 'Permission set label: unassigned from user jdoe@example.com', '2025-03-26T17:10:00.000+0000', '0051X', ''),
+       ('0YM4X', 'changedroleforuser', 'Manage Users', 'Changed role for user ghost@example.com from none to name', '2025-03-26T17:15:00.000+0000', '0051X', '');
+
+CREATE TABLE LoginHistory
+(
+    Id          TEXT PRIMARY KEY,
+    UserId      TEXT,
+    LoginTime   TEXT,
+    LoginType   TEXT,
+    LoginUrl    TEXT,
+    SourceIp    TEXT,
+    Status      TEXT,
+    Application TEXT,
+    Browser     TEXT,
+    Platform    TEXT
+)
+
+INSERT INTO LoginHistory (Id, UserId, LoginTime, LoginType, LoginUrl, SourceIp, Status, Application, Browser, Platform)
+VALUES ('0Ya1X', '0051X', '2025-03-26T16:43:31.000+0000', 'Application', 'login.salesforce.com', '203.0.113.7', 'Success', 'Browser', 'Chrome 134', 'Mac OSX'),
+       ('0Ya2X', '0052X', '2025-03-26T16:45:02.000+0000', 'Application', 'login.salesforce.com', '198.51.100.23', 'Invalid Password', 'Browser', 'Firefox 136', 'Windows 10'),
+       ('0Ya3X', '0051X', '2025-03-26T18:00:00.000+0000', 'Remote Access 2.0', 'login.salesforce.com', '203.0.113.7', 'Success', 'Salesforce for iOS', 'Unknown', 'iPhone iOS');
+
+INSERT INTO ConnectedApplication (ID, Name, CreatedDate, CreatedById, LastModifiedDate)
+VALUES ('0H41X', 'Salesforce for iOS', '2025-01-01T00:00:00.000+0000', '0051X', '2025-01-01T00:00:00.000+0000');
+
+CREATE TABLE OauthToken
+(
+    Id           TEXT PRIMARY KEY,
+    AppName      TEXT,
+    UserId       TEXT,
+    LastUsedDate TEXT,
+    UseCount     INT
+)
+
+INSERT INTO OauthToken (Id, AppName, UserId, LastUsedDate, UseCount)
+VALUES ('0CJ1X', 'Salesforce for iOS', '0051X', '2025-03-01T00:00:00.000+0000', 12),
+       ('0CJ2X', 'Salesforce for iOS', '0051X', '2025-04-01T00:00:00.000+0000', 3),
+       ('0CJ3X', 'Salesforce for iOS', '0052X', '2025-02-01T00:00:00.000+0000', 1);
+
+CREATE TABLE SetupEntityAccess
+(
+    Id              TEXT PRIMARY KEY,
+    ParentId        TEXT,
+    SetupEntityId   TEXT,
+    SetupEntityType TEXT
+)
+
+INSERT INTO SetupEntityAccess (Id, ParentId, SetupEntityId, SetupEntityType)
+VALUES ('0J01X', 'PS2X', '0H41X', 'ConnectedApplication'),
+       ('0J02X', '345X', '0H41X', 'ConnectedApplication'),
+       ('0J03X', 'PS2X', '01p1X', 'ApexClass');
+
+CREATE TABLE QueueSobject
+(
+    Id          TEXT PRIMARY KEY,
+    QueueId     TEXT,
+    SobjectType TEXT
+)
+
+INSERT INTO QueueSobject (Id, QueueId, SobjectType)
+VALUES ('03g1X', '00G3X', 'Case'),
+       ('03g2X', '00G3X', 'Lead');
+
+CREATE TABLE ObjectPermissions
+(
+    Id                          TEXT PRIMARY KEY,
+    ParentId                    TEXT,
+    SobjectType                 TEXT,
+    PermissionsRead             INT,
+    PermissionsCreate           INT,
+    PermissionsEdit             INT,
+    PermissionsDelete           INT,
+    PermissionsViewAllRecords   INT,
+    PermissionsModifyAllRecords INT
+)
+
+INSERT INTO ObjectPermissions (Id, ParentId, SobjectType, PermissionsRead, PermissionsCreate, PermissionsEdit, PermissionsDelete, PermissionsViewAllRecords, PermissionsModifyAllRecords)
+VALUES ('110X', '345X', 'Account', 1, 0, 0, 0, 0, 0),
+       ('111X', '345X', 'Opportunity', 1, 1, 1, 1, 1, 0);
+
+CREATE TABLE FieldPermissions
+(
+    Id              TEXT PRIMARY KEY,
+    ParentId        TEXT,
+    SobjectType     TEXT,
+    Field           TEXT,
+    PermissionsRead INT,
+    PermissionsEdit INT
+)
+
+INSERT INTO FieldPermissions (Id, ParentId, SobjectType, Field, PermissionsRead, PermissionsEdit)
+VALUES ('01k1X', '345X', 'Contact', 'Contact.SSN__c', 1, 0),
+       ('01k2X', '345X', 'Contact', 'Contact.Phone', 1, 1),
+       ('01k3X', 'PS2X', 'Contact', 'Contact.SSN__c', 1, 1);
+
+CREATE TABLE PermissionSetLicense
+(
+    Id             TEXT PRIMARY KEY,
+    MasterLabel    TEXT,
+    DeveloperName  TEXT,
+    TotalLicenses  INT,
+    UsedLicenses   INT,
+    Status         TEXT,
+    ExpirationDate TEXT
+)
+
+CREATE TABLE PermissionSetLicenseAssign
+(
+    Id                     TEXT PRIMARY KEY,
+    AssigneeId             TEXT,
+    PermissionSetLicenseId TEXT
+)
+
+INSERT INTO PermissionSetLicense (Id, MasterLabel, DeveloperName, TotalLicenses, UsedLicenses, Status, ExpirationDate)
+VALUES ('0PL1X', 'Sales Cloud Einstein', 'SalesCloudEinsteinPsl', 10, 1, 'Active', '');
+
+INSERT INTO PermissionSetLicenseAssign (Id, AssigneeId, PermissionSetLicenseId)
+VALUES ('2LA1X', '0051X', '0PL1X');
+
+CREATE TABLE PackageLicense
+(
+    Id              TEXT PRIMARY KEY,
+    NamespacePrefix TEXT,
+    AllowedLicenses INT,
+    UsedLicenses    INT,
+    Status          TEXT,
+    ExpirationDate  TEXT
+)
+
+CREATE TABLE UserPackageLicense
+(
+    Id               TEXT PRIMARY KEY,
+    UserId           TEXT,
+    PackageLicenseId TEXT
+)
+
+INSERT INTO PackageLicense (Id, NamespacePrefix, AllowedLicenses, UsedLicenses, Status, ExpirationDate)
+VALUES ('050A1X', 'SBQQ', 25, 1, 'Active', ''),
+       ('050A2X', 'dsfs', -1, 0, 'Active', '');
+
+INSERT INTO UserPackageLicense (Id, UserId, PackageLicenseId)
+VALUES ('051A1X', '0051X', '050A1X');
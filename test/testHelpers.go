@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/conductorone/baton-salesforce/pkg/connector/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -162,6 +163,37 @@ func resolveSubqueries(ctx context.Context, db *sql.DB, queryString string) (str
 	return result, nil
 }
 
+// soqlDatetimePattern matches the unquoted datetime literals SOQL uses, e.g.
+// "SystemModstamp >= 2025-03-26T16:43:31Z".
+var soqlDatetimePattern = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z\b`)
+
+// quoteDatetimeLiterals turns SOQL datetime literals into strings in the same
+// format the fixtures store timestamps in, so ramsql can compare them as text.
+func quoteDatetimeLiterals(queryString string) string {
+	return soqlDatetimePattern.ReplaceAllStringFunc(queryString, func(match string) string {
+		parsed, err := time.Parse(time.RFC3339, match)
+		if err != nil {
+			return match
+		}
+		return fmt.Sprintf("'%s'", parsed.UTC().Format("2006-01-02T15:04:05.000-0700"))
+	})
+}
+
+// soqlBooleanPattern matches the boolean literals in SOQL conditions, e.g.
+// "IsActive = true".
+var soqlBooleanPattern = regexp.MustCompile(`= (true|false)\b`)
+
+// numberBooleanLiterals turns SOQL boolean literals into the 1 and 0 the
+// fixtures store in INT columns, since ramsql can't compare the two.
+func numberBooleanLiterals(queryString string) string {
+	return soqlBooleanPattern.ReplaceAllStringFunc(queryString, func(match string) string {
+		if match == "= true" {
+			return "= 1"
+		}
+		return "= 0"
+	})
+}
+
 func query(ctx context.Context, db *sql.DB, queryString string) ([]simpleforce.SObject, error) {
 	// The ramsql backing store has no relationship columns, so drop the nested
 	// license field from the User query. NewQuery joins fields with ", " while the
@@ -172,6 +204,10 @@ func query(ctx context.Context, db *sql.DB, queryString string) ([]simpleforce.S
 	hackString = strings.ReplaceAll(hackString, ".Name", "")
 	hackString = strings.ReplaceAll(hackString, "Fields(standard)", "Id,*")
 
+	hackString = quoteDatetimeLiterals(hackString)
+	hackString = numberBooleanLiterals(hackString)
+	hackString, orderBy, limit := cutOrderBy(hackString)
+
 	var err error
 	hackString, err = resolveSubqueries(ctx, db, hackString)
 	if err != nil {
@@ -212,9 +248,45 @@ func query(ctx context.Context, db *sql.DB, queryString string) ([]simpleforce.S
 		output = append(output, m)
 	}
 
+	sortRows(output, orderBy)
+	if limit >= 0 && len(output) > limit {
+		output = output[:limit]
+	}
 	return output, nil
 }
 
+var orderByPattern = regexp.MustCompile(`\s+ORDER BY\s+(.+?)(?:\s+LIMIT\s+(\d+))?\s*$`)
+
+// cutOrderBy strips the trailing ORDER BY and LIMIT clauses, which ramsql does
+// not apply reliably, so that query can sort and truncate the rows itself.
+// The returned limit is -1 when there is none.
+func cutOrderBy(queryString string) (string, []string, int) {
+	matches := orderByPattern.FindStringSubmatch(queryString)
+	if matches == nil {
+		return queryString, nil, -1
+	}
+	columns := strings.Split(matches[1], ",")
+	for i, column := range columns {
+		columns[i] = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(column), " ASC"))
+	}
+	limit := -1
+	if matches[2] != "" {
+		limit, _ = strconv.Atoi(matches[2])
+	}
+	return queryString[:len(queryString)-len(matches[0])], columns, limit
+}
+
+func sortRows(rows []simpleforce.SObject, columns []string) {
+	slices.SortStableFunc(rows, func(a, b simpleforce.SObject) int {
+		for _, column := range columns {
+			if c := strings.Compare(fmt.Sprint(a[column]), fmt.Sprint(b[column])); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
 // QueryResult holds the response data from an SOQL query.
 type QueryResult struct {
 	TotalSize      int                   `json:"totalSize"`
@@ -228,6 +300,7 @@ func FixturesServer(ctx context.Context) (*httptest.Server, *sql.DB, error) {
 	if err != nil {
 		return nil, nil, err
 	}
+	jobs := newBulkJobs()
 
 	server := httptest.NewServer(
 		http.HandlerFunc(
@@ -236,7 +309,12 @@ func FixturesServer(ctx context.Context) (*httptest.Server, *sql.DB, error) {
 
 				path := request.URL.Path
 				var output []byte
+				var err error
 				switch {
+				case strings.Contains(path, "jobs/query"):
+					output, err = jobs.handle(ctx, db, writer, request)
+				case strings.HasSuffix(path, "/composite/sobjects") && request.Method == http.MethodDelete:
+					output, err = handleCompositeDelete(ctx, db, request)
 				case strings.Contains(path, "sobjects"):
 					switch request.Method {
 					case http.MethodGet:
@@ -269,9 +347,9 @@ func FixturesServer(ctx context.Context) (*httptest.Server, *sql.DB, error) {
 					return
 				}
 
+				used := 1
 				limit := 100
-				remaining := 100
-				writer.Header().Set(client.RateLimitHeaderKey, fmt.Sprintf(client.RateLimitFmt, limit, remaining))
+				writer.Header().Set(client.RateLimitHeaderKey, fmt.Sprintf(client.RateLimitFmt, used, limit))
 				writer.WriteHeader(http.StatusOK)
 
 				_, err = writer.Write(output) //nolint:gosec // this is a test helper
@@ -331,6 +409,46 @@ func handleDelete(ctx context.Context, db *sql.DB, request *http.Request) error
 	return err
 }
 
+// handleCompositeDelete serves an sObject Collections delete. The IDs don't
+// say which table they belong to, so each one is tried against every table.
+func handleCompositeDelete(ctx context.Context, db *sql.DB, request *http.Request) ([]byte, error) {
+	data, err := os.ReadFile("../../test/fixtures/dump.sql")
+	if err != nil {
+		return nil, err
+	}
+	tables := regexp.MustCompile(`CREATE TABLE "?(\w+)"?`).FindAllStringSubmatch(string(data), -1)
+
+	results := make([]map[string]interface{}, 0)
+	for _, id := range strings.Split(request.URL.Query().Get("ids"), ",") {
+		deleted := false
+		for _, table := range tables {
+			result, err := db.ExecContext( //nolint:gosec // test-only mock server; table and id come from internal test routing, not user input
+				ctx,
+				fmt.Sprintf(`DELETE FROM "%s" WHERE Id = '%s'`, table[1], id),
+			)
+			if err != nil {
+				continue
+			}
+			if count, err := result.RowsAffected(); err == nil && count > 0 {
+				deleted = true
+				break
+			}
+		}
+		if deleted {
+			results = append(results, map[string]interface{}{"id": id, "success": true, "errors": []interface{}{}})
+			continue
+		}
+		results = append(results, map[string]interface{}{
+			"id":      id,
+			"success": false,
+			"errors": []map[string]interface{}{
+				{"statusCode": "ENTITY_IS_DELETED", "message": "entity is deleted"},
+			},
+		})
+	}
+	return json.Marshal(results)
+}
+
 func handlePatch(ctx context.Context, db *sql.DB, request *http.Request) ([]byte, error) {
 	tableName, id := parsePath(request)
 	body, err := getBody(request)
@@ -342,11 +460,17 @@ func handlePatch(ctx context.Context, db *sql.DB, request *http.Request) ([]byte
 		var valueString string
 		switch typedValue := value.(type) {
 		case string:
-			valueString = typedValue
+			valueString = fmt.Sprintf("'%s'", typedValue)
 		case int:
-			valueString = strconv.Itoa(typedValue)
+			valueString = fmt.Sprintf("'%d'", typedValue)
 		case float64:
-			valueString = strconv.FormatFloat(typedValue, 'f', 0, 64)
+			valueString = fmt.Sprintf("'%s'", strconv.FormatFloat(typedValue, 'f', 0, 64))
+		case bool:
+			// Booleans are stored as 1 and 0 in INT columns.
+			valueString = "0"
+			if typedValue {
+				valueString = "1"
+			}
 		default:
 			return nil, fmt.Errorf("unknown type: %T", value)
 		}
@@ -354,7 +478,7 @@ func handlePatch(ctx context.Context, db *sql.DB, request *http.Request) ([]byte
 		conditions = append(
 			conditions,
 			fmt.Sprintf(
-				`"%s" = '%s'`,
+				`"%s" = %s`,
 				strings.ToLower(key),
 				valueString,
 			),